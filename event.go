@@ -1,25 +1,46 @@
 package goearth
 
-import "context"
+import (
+	"context"
+	"sync"
+)
 
 type VoidEvent struct {
 	handlers []VoidHandler
+	waiters  eventWaiters[struct{}]
 }
 type VoidHandler func()
 
-func (e *VoidEvent) Register(handler VoidHandler) {
-	e.handlers = append(e.handlers, handler)
+func (e *VoidEvent) Register(handlers ...VoidHandler) {
+	e.handlers = append(e.handlers, handlers...)
 }
 
 func (e *VoidEvent) Dispatch() {
 	for _, handler := range e.handlers {
 		handler()
 	}
+	e.waiters.dispatch(struct{}{})
+}
+
+// Next waits for the next dispatch of the event.
+// Returns the cause of the context's cancellation if it is done before the event is dispatched.
+func (e *VoidEvent) Next(ctx context.Context) error {
+	_, err := e.waiters.next(ctx)
+	return err
+}
+
+// Chan returns a channel that receives a value each time the event is dispatched.
+// The channel is closed once the context is done.
+// The event dispatcher blocks until the value is received or the context is done,
+// so the channel should be drained promptly.
+func (e *VoidEvent) Chan(ctx context.Context) <-chan struct{} {
+	return e.waiters.channel(ctx)
 }
 
 type Event[T any] struct {
 	setup    func(args T)
 	handlers []EventHandler[T]
+	waiters  eventWaiters[T]
 }
 type EventHandler[T any] func(e T)
 
@@ -38,6 +59,96 @@ func (e *Event[T]) Dispatch(args T) {
 		}
 		handler(args)
 	}
+	if e.setup != nil {
+		e.setup(args)
+	}
+	e.waiters.dispatch(args)
+}
+
+// Next waits for the next dispatch of the event and returns its arguments.
+// Returns the cause of the context's cancellation if it is done before the event is dispatched.
+func (e *Event[T]) Next(ctx context.Context) (T, error) {
+	return e.waiters.next(ctx)
+}
+
+// Chan returns a channel that receives the arguments each time the event is dispatched.
+// The channel is closed once the context is done.
+// The event dispatcher blocks until the value is received or the context is done,
+// so the channel should be drained promptly.
+func (e *Event[T]) Chan(ctx context.Context) <-chan T {
+	return e.waiters.channel(ctx)
+}
+
+// eventWaiter represents a caller waiting on an event via Next or Chan.
+type eventWaiter[T any] struct {
+	ctx  context.Context
+	ch   chan T
+	once bool
+}
+
+// eventWaiters holds the set of callers waiting on an event.
+type eventWaiters[T any] struct {
+	mtx     sync.Mutex
+	waiters map[*eventWaiter[T]]struct{}
+}
+
+func (w *eventWaiters[T]) add(waiter *eventWaiter[T]) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	if w.waiters == nil {
+		w.waiters = map[*eventWaiter[T]]struct{}{}
+	}
+	w.waiters[waiter] = struct{}{}
+}
+
+func (w *eventWaiters[T]) remove(waiter *eventWaiter[T]) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	delete(w.waiters, waiter)
+}
+
+func (w *eventWaiters[T]) next(ctx context.Context) (args T, err error) {
+	waiter := &eventWaiter[T]{ctx: ctx, ch: make(chan T, 1), once: true}
+	w.add(waiter)
+	defer w.remove(waiter)
+
+	select {
+	case args = <-waiter.ch:
+	case <-ctx.Done():
+		err = context.Cause(ctx)
+	}
+	return
+}
+
+func (w *eventWaiters[T]) channel(ctx context.Context) <-chan T {
+	waiter := &eventWaiter[T]{ctx: ctx, ch: make(chan T)}
+	w.add(waiter)
+	context.AfterFunc(ctx, func() {
+		// The lock is held while dispatching, so the channel
+		// cannot be closed during a send.
+		w.remove(waiter)
+		close(waiter.ch)
+	})
+	return waiter.ch
+}
+
+func (w *eventWaiters[T]) dispatch(args T) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	for waiter := range w.waiters {
+		if waiter.once {
+			select {
+			case waiter.ch <- args:
+			default:
+			}
+			continue
+		}
+		select {
+		case waiter.ch <- args:
+		case <-waiter.ctx.Done():
+		}
+	}
 }
 
 type InitArgs struct {
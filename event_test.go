@@ -0,0 +1,78 @@
+package goearth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEventNext(t *testing.T) {
+	var e Event[int]
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	result := make(chan int)
+	go func() {
+		v, err := e.Next(ctx)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+		result <- v
+	}()
+
+	// wait for the waiter to be registered
+	for {
+		e.waiters.mtx.Lock()
+		n := len(e.waiters.waiters)
+		e.waiters.mtx.Unlock()
+		if n > 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	e.Dispatch(31337)
+	if v := <-result; v != 31337 {
+		t.Fatalf("incorrect value, expected: %d, actual: %d", 31337, v)
+	}
+
+	if len(e.waiters.waiters) != 0 {
+		t.Fatalf("waiter was not removed after receiving the event")
+	}
+}
+
+func TestEventNextCanceled(t *testing.T) {
+	var e Event[int]
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := e.Next(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("incorrect error, expected: %v, actual: %v", context.Canceled, err)
+	}
+}
+
+func TestEventChan(t *testing.T) {
+	var e VoidEvent
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := e.Chan(ctx)
+
+	go func() {
+		for range 3 {
+			e.Dispatch()
+		}
+		cancel()
+	}()
+
+	n := 0
+	for range ch {
+		n++
+	}
+	if n != 3 {
+		t.Fatalf("incorrect number of values received, expected: %d, actual: %d", 3, n)
+	}
+}
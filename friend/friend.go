@@ -0,0 +1,101 @@
+package friend
+
+import (
+	g "xabbo.b7c.io/goearth"
+)
+
+// Info contains information about a friend.
+type Info struct {
+	Id                   g.Id
+	Name                 string
+	Gender               int
+	Online               bool
+	CanFollow            bool
+	Figure               string
+	CategoryId           int
+	Motto                string
+	RealName             string
+	FacebookId           string
+	PersistedMessageUser bool
+	VipMember            bool
+	PocketHabboUser      bool
+	RelationshipStatus   int16
+}
+
+// Category represents a friend list category.
+type Category struct {
+	Id   int
+	Name string
+}
+
+// Init holds the messenger limits and friend categories sent when the messenger is initialized.
+type Init struct {
+	UserLimit     int
+	NormalLimit   int
+	ExtendedLimit int
+	Categories    []Category
+}
+
+// Fragment represents a fragment of the friend list.
+// The friend list is split into multiple fragments when it is loaded.
+type Fragment struct {
+	Total   int
+	Index   int
+	Friends []Info
+}
+
+// UpdateType represents the type of a friend list update.
+type UpdateType int
+
+const (
+	Removed UpdateType = -1
+	Updated UpdateType = 0
+	Added   UpdateType = 1
+)
+
+// Update represents a single update to the friend list.
+type Update struct {
+	Type UpdateType
+	// Id is the ID of the removed friend, or the ID of the friend in Info if added or updated.
+	Id   g.Id
+	Info Info
+}
+
+func (update *Update) Parse(p *g.Packet, pos *int) {
+	*update = Update{Type: UpdateType(p.ReadIntPtr(pos))}
+	if update.Type == Removed {
+		p.ReadPtr(pos, &update.Id)
+	} else {
+		p.ReadPtr(pos, &update.Info)
+		update.Id = update.Info.Id
+	}
+}
+
+// ListUpdate represents a batch of updates to the friend list and its categories.
+type ListUpdate struct {
+	Categories []Category
+	Updates    []Update
+}
+
+// Request represents a friend request.
+type Request struct {
+	Id     g.Id
+	Name   string
+	Figure string
+}
+
+// Message represents a private message received from a friend.
+type Message struct {
+	SenderId         g.Id
+	Message          string
+	SecondsSinceSent int
+	ExtraData        string
+}
+
+func (msg *Message) Parse(p *g.Packet, pos *int) {
+	*msg = Message{}
+	p.ReadPtr(pos, &msg.SenderId, &msg.Message, &msg.SecondsSinceSent)
+	if *pos < p.Length() {
+		p.ReadPtr(pos, &msg.ExtraData)
+	}
+}
@@ -0,0 +1,79 @@
+package friend
+
+import g "xabbo.b7c.io/goearth"
+
+// Args holds the arguments for friend events.
+type Args struct {
+	Friend Info
+}
+
+// UpdateArgs holds the arguments for friend update events.
+type UpdateArgs struct {
+	Pre    Info // Pre is the previous state of the friend before the update.
+	Friend Info // Friend is the current state of the friend after the update.
+}
+
+// ListArgs holds the arguments for friend list events.
+type ListArgs struct {
+	Friends []Info
+}
+
+// MessageArgs holds the arguments for private message events.
+type MessageArgs struct {
+	// Friend is the sender of the message, or nil if the sender is not in the friend list.
+	Friend  *Info
+	Message Message
+}
+
+// RequestArgs holds the arguments for friend request events.
+type RequestArgs struct {
+	Request Request
+}
+
+// Loaded registers an event handler that is invoked when the friend list is fully loaded.
+func (mgr *Manager) Loaded(handlers ...g.EventHandler[ListArgs]) *g.Event[ListArgs] {
+	mgr.loaded.Register(handlers...)
+	return &mgr.loaded
+}
+
+// Added registers an event handler that is invoked when a friend is added.
+func (mgr *Manager) Added(handlers ...g.EventHandler[Args]) *g.Event[Args] {
+	mgr.added.Register(handlers...)
+	return &mgr.added
+}
+
+// Updated registers an event handler that is invoked when a friend is updated.
+func (mgr *Manager) Updated(handlers ...g.EventHandler[UpdateArgs]) *g.Event[UpdateArgs] {
+	mgr.updated.Register(handlers...)
+	return &mgr.updated
+}
+
+// Removed registers an event handler that is invoked when a friend is removed.
+func (mgr *Manager) Removed(handlers ...g.EventHandler[Args]) *g.Event[Args] {
+	mgr.removed.Register(handlers...)
+	return &mgr.removed
+}
+
+// CameOnline registers an event handler that is invoked when a friend comes online.
+func (mgr *Manager) CameOnline(handlers ...g.EventHandler[Args]) *g.Event[Args] {
+	mgr.cameOnline.Register(handlers...)
+	return &mgr.cameOnline
+}
+
+// WentOffline registers an event handler that is invoked when a friend goes offline.
+func (mgr *Manager) WentOffline(handlers ...g.EventHandler[Args]) *g.Event[Args] {
+	mgr.wentOffline.Register(handlers...)
+	return &mgr.wentOffline
+}
+
+// MessageReceived registers an event handler that is invoked when a private message is received.
+func (mgr *Manager) MessageReceived(handlers ...g.EventHandler[MessageArgs]) *g.Event[MessageArgs] {
+	mgr.messageReceived.Register(handlers...)
+	return &mgr.messageReceived
+}
+
+// RequestReceived registers an event handler that is invoked when a friend request is received.
+func (mgr *Manager) RequestReceived(handlers ...g.EventHandler[RequestArgs]) *g.Event[RequestArgs] {
+	mgr.requestReceived.Register(handlers...)
+	return &mgr.requestReceived
+}
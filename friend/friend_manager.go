@@ -0,0 +1,334 @@
+package friend
+
+import (
+	"strings"
+	"sync"
+
+	g "xabbo.b7c.io/goearth"
+	"xabbo.b7c.io/goearth/in"
+	"xabbo.b7c.io/goearth/internal/debug"
+	"xabbo.b7c.io/goearth/out"
+)
+
+var dbg = debug.NewLogger("[friend]")
+
+// Manager tracks the state of the friend list and messenger.
+type Manager struct {
+	ix              g.Interceptor
+	loaded          g.Event[ListArgs]
+	added           g.Event[Args]
+	updated         g.Event[UpdateArgs]
+	removed         g.Event[Args]
+	cameOnline      g.Event[Args]
+	wentOffline     g.Event[Args]
+	messageReceived g.Event[MessageArgs]
+	requestReceived g.Event[RequestArgs]
+
+	mtx        *sync.RWMutex
+	init       Init
+	friends    map[g.Id]Info
+	requests   map[g.Id]Request
+	fragTotal  int
+	fragments  map[int][]Info
+	categories map[int]Category
+}
+
+// NewManager creates a new friend Manager using the provided extension.
+func NewManager(ix g.Interceptor) *Manager {
+	mgr := &Manager{
+		ix:         ix,
+		mtx:        &sync.RWMutex{},
+		friends:    map[g.Id]Info{},
+		requests:   map[g.Id]Request{},
+		categories: map[int]Category{},
+	}
+	ix.Intercept(in.MessengerInit).With(mgr.handleMessengerInit)
+	ix.Intercept(in.FriendListFragment).With(mgr.handleFriendListFragment)
+	ix.Intercept(in.FriendListUpdate).With(mgr.handleFriendListUpdate)
+	ix.Intercept(in.NewConsole).With(mgr.handleNewConsole)
+	ix.Intercept(in.FriendRequests).With(mgr.handleFriendRequests)
+	ix.Intercept(in.NewFriendRequest).With(mgr.handleNewFriendRequest)
+	return mgr
+}
+
+// Limit returns the maximum number of friends the user may have.
+func (mgr *Manager) Limit() int {
+	mgr.mtx.RLock()
+	defer mgr.mtx.RUnlock()
+	return mgr.init.UserLimit
+}
+
+// Friend gets the friend with the specified ID.
+func (mgr *Manager) Friend(id g.Id) *Info {
+	mgr.mtx.RLock()
+	defer mgr.mtx.RUnlock()
+	if friend, ok := mgr.friends[id]; ok {
+		return &friend
+	} else {
+		return nil
+	}
+}
+
+// FriendByName gets the friend with the specified name. The name is case-insensitive.
+func (mgr *Manager) FriendByName(name string) *Info {
+	mgr.mtx.RLock()
+	defer mgr.mtx.RUnlock()
+	for _, friend := range mgr.friends {
+		if strings.EqualFold(friend.Name, name) {
+			return &friend
+		}
+	}
+	return nil
+}
+
+// Friends iterates over all friends.
+func (mgr *Manager) Friends(yield func(friend Info) bool) {
+	mgr.mtx.RLock()
+	for _, friend := range mgr.friends {
+		mgr.mtx.RUnlock()
+		if !yield(friend) {
+			return
+		}
+		mgr.mtx.RLock()
+	}
+	mgr.mtx.RUnlock()
+}
+
+// FriendCount returns the number of friends.
+func (mgr *Manager) FriendCount() int {
+	mgr.mtx.RLock()
+	defer mgr.mtx.RUnlock()
+	return len(mgr.friends)
+}
+
+// Category gets the friend list category with the specified ID.
+func (mgr *Manager) Category(id int) *Category {
+	mgr.mtx.RLock()
+	defer mgr.mtx.RUnlock()
+	if category, ok := mgr.categories[id]; ok {
+		return &category
+	} else {
+		return nil
+	}
+}
+
+// Requests iterates over all pending friend requests.
+func (mgr *Manager) Requests(yield func(req Request) bool) {
+	mgr.mtx.RLock()
+	for _, req := range mgr.requests {
+		mgr.mtx.RUnlock()
+		if !yield(req) {
+			return
+		}
+		mgr.mtx.RLock()
+	}
+	mgr.mtx.RUnlock()
+}
+
+// SendMessage sends a private message to the friend with the specified ID.
+func (mgr *Manager) SendMessage(id g.Id, text string) {
+	mgr.ix.Send(out.SendMsg, id, text)
+}
+
+// Follow follows the friend with the specified ID into their room.
+func (mgr *Manager) Follow(id g.Id) {
+	mgr.ix.Send(out.FollowFriend, id)
+}
+
+// Request sends a friend request to the user with the specified name.
+func (mgr *Manager) Request(name string) {
+	mgr.ix.Send(out.RequestFriend, name)
+}
+
+// Remove removes the friends with the specified IDs.
+func (mgr *Manager) Remove(ids ...g.Id) {
+	if len(ids) == 0 {
+		return
+	}
+	mgr.ix.Send(out.RemoveFriend, idValues(ids)...)
+}
+
+// Accept accepts the friend requests from the users with the specified IDs.
+func (mgr *Manager) Accept(ids ...g.Id) {
+	if len(ids) == 0 {
+		return
+	}
+	mgr.removeRequests(ids)
+	mgr.ix.Send(out.AcceptFriend, idValues(ids)...)
+}
+
+// Decline declines the friend requests from the users with the specified IDs.
+func (mgr *Manager) Decline(ids ...g.Id) {
+	if len(ids) == 0 {
+		return
+	}
+	mgr.removeRequests(ids)
+	mgr.ix.Send(out.DeclineFriend, append([]any{false}, idValues(ids)...)...)
+}
+
+// DeclineAll declines all pending friend requests.
+func (mgr *Manager) DeclineAll() {
+	mgr.mtx.Lock()
+	clear(mgr.requests)
+	mgr.mtx.Unlock()
+	mgr.ix.Send(out.DeclineFriend, true, g.Length(0))
+}
+
+// idValues converts the list of IDs into length-prefixed packet values.
+func idValues(ids []g.Id) []any {
+	values := make([]any, 0, 1+len(ids))
+	values = append(values, g.Length(len(ids)))
+	for _, id := range ids {
+		values = append(values, id)
+	}
+	return values
+}
+
+func (mgr *Manager) removeRequests(ids []g.Id) {
+	mgr.mtx.Lock()
+	defer mgr.mtx.Unlock()
+	for _, id := range ids {
+		delete(mgr.requests, id)
+	}
+}
+
+func (mgr *Manager) setCategories(categories []Category) {
+	mgr.categories = make(map[int]Category, len(categories))
+	for _, category := range categories {
+		mgr.categories[category.Id] = category
+	}
+}
+
+// handlers
+
+func (mgr *Manager) handleMessengerInit(e *g.Intercept) {
+	var init Init
+	e.Packet.Read(&init)
+
+	mgr.mtx.Lock()
+	defer mgr.mtx.Unlock()
+
+	mgr.init = init
+	mgr.setCategories(init.Categories)
+	dbg.Printf("messenger initialized (limit: %d, categories: %d)", init.UserLimit, len(init.Categories))
+}
+
+func (mgr *Manager) handleFriendListFragment(e *g.Intercept) {
+	var frag Fragment
+	e.Packet.Read(&frag)
+
+	mgr.mtx.Lock()
+	if frag.Index == 0 || frag.Total != mgr.fragTotal || mgr.fragments == nil {
+		mgr.fragTotal = frag.Total
+		mgr.fragments = map[int][]Info{}
+	}
+	mgr.fragments[frag.Index] = frag.Friends
+	dbg.Printf("received fragment %d/%d (%d friends)", frag.Index+1, frag.Total, len(frag.Friends))
+
+	if len(mgr.fragments) < mgr.fragTotal {
+		mgr.mtx.Unlock()
+		return
+	}
+
+	friends := []Info{}
+	mgr.friends = map[g.Id]Info{}
+	for i := range mgr.fragTotal {
+		for _, friend := range mgr.fragments[i] {
+			mgr.friends[friend.Id] = friend
+			friends = append(friends, friend)
+		}
+	}
+	mgr.fragments = nil
+	mgr.mtx.Unlock()
+
+	dbg.Printf("loaded %d friend(s)", len(friends))
+	mgr.loaded.Dispatch(ListArgs{friends})
+}
+
+func (mgr *Manager) handleFriendListUpdate(e *g.Intercept) {
+	var update ListUpdate
+	e.Packet.Read(&update)
+
+	mgr.mtx.Lock()
+	mgr.setCategories(update.Categories)
+	mgr.mtx.Unlock()
+
+	for _, u := range update.Updates {
+		switch u.Type {
+		case Added:
+			mgr.mtx.Lock()
+			mgr.friends[u.Id] = u.Info
+			mgr.mtx.Unlock()
+			dbg.Printf("added friend %q", u.Info.Name)
+			mgr.added.Dispatch(Args{u.Info})
+		case Updated:
+			mgr.mtx.Lock()
+			pre, exists := mgr.friends[u.Id]
+			mgr.friends[u.Id] = u.Info
+			mgr.mtx.Unlock()
+			if !exists {
+				dbg.Printf("WARNING: failed to find friend to update (ID: %d)", u.Id)
+				mgr.added.Dispatch(Args{u.Info})
+				continue
+			}
+			mgr.updated.Dispatch(UpdateArgs{pre, u.Info})
+			if !pre.Online && u.Info.Online {
+				dbg.Printf("%q came online", u.Info.Name)
+				mgr.cameOnline.Dispatch(Args{u.Info})
+			} else if pre.Online && !u.Info.Online {
+				dbg.Printf("%q went offline", u.Info.Name)
+				mgr.wentOffline.Dispatch(Args{u.Info})
+			}
+		case Removed:
+			mgr.mtx.Lock()
+			friend, exists := mgr.friends[u.Id]
+			delete(mgr.friends, u.Id)
+			mgr.mtx.Unlock()
+			if !exists {
+				dbg.Printf("WARNING: failed to find friend to remove (ID: %d)", u.Id)
+				continue
+			}
+			dbg.Printf("removed friend %q", friend.Name)
+			mgr.removed.Dispatch(Args{friend})
+		default:
+			dbg.Printf("WARNING: unknown friend list update type: %d", u.Type)
+		}
+	}
+}
+
+func (mgr *Manager) handleNewConsole(e *g.Intercept) {
+	var msg Message
+	e.Packet.Read(&msg)
+
+	mgr.messageReceived.Dispatch(MessageArgs{
+		Friend:  mgr.Friend(msg.SenderId),
+		Message: msg,
+	})
+}
+
+func (mgr *Manager) handleFriendRequests(e *g.Intercept) {
+	var total int
+	var requests []Request
+	e.Packet.Read(&total, &requests)
+
+	mgr.mtx.Lock()
+	defer mgr.mtx.Unlock()
+
+	mgr.requests = make(map[g.Id]Request, len(requests))
+	for _, req := range requests {
+		mgr.requests[req.Id] = req
+	}
+	dbg.Printf("received %d friend request(s)", len(requests))
+}
+
+func (mgr *Manager) handleNewFriendRequest(e *g.Intercept) {
+	var req Request
+	e.Packet.Read(&req)
+
+	mgr.mtx.Lock()
+	mgr.requests[req.Id] = req
+	mgr.mtx.Unlock()
+
+	dbg.Printf("received friend request from %q", req.Name)
+	mgr.requestReceived.Dispatch(RequestArgs{req})
+}
@@ -0,0 +1,61 @@
+package friend
+
+import (
+	"reflect"
+	"testing"
+
+	g "xabbo.b7c.io/goearth"
+)
+
+func TestListUpdate(t *testing.T) {
+	pkt := &g.Packet{Client: g.Flash, Header: g.Header{Dir: g.In}}
+	// categories
+	pkt.Write(1, 1, "category")
+	// updates
+	pkt.Write(2)
+	pkt.Write(int(Removed), 1)
+	pkt.Write(int(Added), 2, "friend", 1, true, true, "hd-180-1", 1, "motto", "", "", false, false, false, int16(0))
+
+	var update ListUpdate
+	pkt.Pos = 0
+	pkt.Read(&update)
+
+	if pkt.Pos != pkt.Length() {
+		t.Fatal("parser failed to read entire packet")
+	}
+
+	expected := ListUpdate{
+		Categories: []Category{{Id: 1, Name: "category"}},
+		Updates: []Update{
+			{Type: Removed, Id: 1},
+			{Type: Added, Id: 2, Info: Info{Id: 2, Name: "friend", Gender: 1, Online: true,
+				CanFollow: true, Figure: "hd-180-1", CategoryId: 1, Motto: "motto"}},
+		},
+	}
+	if !reflect.DeepEqual(expected, update) {
+		t.Fatalf("incorrect list update, expected: %+v, actual: %+v", expected, update)
+	}
+}
+
+func TestMessage(t *testing.T) {
+	for _, extra := range []bool{false, true} {
+		pkt := &g.Packet{Client: g.Flash, Header: g.Header{Dir: g.In}}
+		pkt.Write(1, "hello", 5)
+		expected := Message{SenderId: 1, Message: "hello", SecondsSinceSent: 5}
+		if extra {
+			pkt.Write("extra")
+			expected.ExtraData = "extra"
+		}
+
+		var msg Message
+		pkt.Pos = 0
+		pkt.Read(&msg)
+
+		if pkt.Pos != pkt.Length() {
+			t.Fatal("parser failed to read entire packet")
+		}
+		if msg != expected {
+			t.Fatalf("incorrect message, expected: %+v, actual: %+v", expected, msg)
+		}
+	}
+}
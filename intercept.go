@@ -162,15 +162,18 @@ type inlineInterceptor struct {
 	bindOnce    sync.Once
 	block       bool
 	cond        func(*Packet) bool
-	result      chan *Packet
 	ref         InterceptRef
+
+	// mtx guards done and the result channel, so that a result
+	// is never sent after the channel is closed.
+	mtx    sync.Mutex
+	done   bool
+	result chan *Packet
 }
 
 func NewInlineInterceptor(interceptor Interceptor, identifiers []Identifier) InlineInterceptor {
 	ctx, cancel := context.WithCancel(context.Background())
-	result := make(chan *Packet, 1)
-	context.AfterFunc(ctx, func() { close(result) })
-	return &inlineInterceptor{
+	i := &inlineInterceptor{
 		ix:          interceptor,
 		identifiers: identifiers,
 		ctx:         ctx,
@@ -178,6 +181,29 @@ func NewInlineInterceptor(interceptor Interceptor, identifiers []Identifier) Inl
 		timeout:     time.AfterFunc(time.Minute, cancel),
 		result:      make(chan *Packet, 1),
 	}
+	context.AfterFunc(ctx, i.close)
+	return i
+}
+
+// Closes the result channel once the interceptor times out, is canceled or receives its result.
+func (i *inlineInterceptor) close() {
+	i.mtx.Lock()
+	defer i.mtx.Unlock()
+	i.done = true
+	close(i.result)
+}
+
+// Sends the result if the interceptor is not done, and reports whether it was sent.
+func (i *inlineInterceptor) send(pkt *Packet) bool {
+	i.mtx.Lock()
+	defer i.mtx.Unlock()
+	if i.done {
+		return false
+	}
+	i.done = true
+	// The channel is buffered and only ever receives one result, so this never blocks.
+	i.result <- pkt
+	return true
 }
 
 // Handles the intercept logic for an inline interceptor.
@@ -195,10 +221,7 @@ func (i *inlineInterceptor) interceptHandler(e *Intercept) {
 	}
 
 	e.dereg = true
-	select {
-	case <-i.ctx.Done():
-		// Timed out.
-	case i.result <- e.Packet.Copy():
+	if i.send(e.Packet.Copy()) {
 		if i.block {
 			e.Block()
 		}
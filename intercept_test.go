@@ -0,0 +1,68 @@
+package goearth_test
+
+import (
+	"testing"
+	"time"
+
+	g "xabbo.b7c.io/goearth"
+	"xabbo.b7c.io/goearth/internal/testix"
+)
+
+func TestInlineInterceptorCancelDispatch(t *testing.T) {
+	id := g.In.Id("Test")
+	ix := testix.New(g.Flash)
+	defer ix.Close()
+	ix.Resolve(id)
+
+	for range 1000 {
+		recv := ix.Recv(id)
+		ch := recv.Await()
+
+		done := make(chan struct{})
+		go func() {
+			ix.Dispatch(id, 1)
+			close(done)
+		}()
+		recv.Cancel()
+
+		if pkt, ok := <-ch; ok && pkt.ReadInt() != 1 {
+			t.Fatalf("incorrect packet received")
+		}
+		if _, ok := <-ch; ok {
+			t.Fatalf("result channel was not closed")
+		}
+		<-done
+	}
+}
+
+func TestInlineInterceptorCancelDuringCondition(t *testing.T) {
+	id := g.In.Id("Test")
+	ix := testix.New(g.Flash)
+	defer ix.Close()
+	ix.Resolve(id)
+
+	for range 100 {
+		recv := ix.Recv(id)
+		recv.If(func(*g.Packet) bool {
+			// Cancel after the handler's initial check, and give the result channel time to close.
+			recv.Cancel()
+			time.Sleep(time.Millisecond)
+			return true
+		})
+		ch := recv.Await()
+		ix.Dispatch(id, 1)
+		if _, ok := <-ch; ok {
+			t.Fatalf("result was sent after the interceptor was canceled")
+		}
+	}
+}
+
+func TestInlineInterceptorWaitTimeout(t *testing.T) {
+	id := g.In.Id("Test")
+	ix := testix.New(g.Flash)
+	defer ix.Close()
+
+	if pkt := ix.Recv(id).TimeoutMs(10).Wait(); pkt != nil {
+		t.Fatalf("incorrect result, expected: nil, actual: %v", pkt)
+	}
+}
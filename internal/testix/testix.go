@@ -0,0 +1,203 @@
+// Package testix provides a fake interceptor for testing game state managers.
+package testix
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	g "xabbo.b7c.io/goearth"
+)
+
+// ResponseDelay is the delay before packets dispatched with DispatchLater are dispatched,
+// giving the code under test time to start waiting for them after sending a request.
+const ResponseDelay = 10 * time.Millisecond
+
+// Responder responds to a packet sent by the code under test.
+type Responder func(ix *Interceptor, pkt *g.Packet)
+
+// Interceptor is a fake interceptor that records sent packets
+// and dispatches packets to registered intercept handlers.
+// Headers are assigned to identifiers as they are used.
+type Interceptor struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	client g.Client
+
+	mtx     *sync.Mutex
+	headers *g.Headers
+	next    map[g.Direction]uint16
+	groups  []*group
+	sent    []*g.Packet
+	respond map[g.Header][]Responder
+}
+
+type group struct {
+	ix          *Interceptor
+	identifiers map[g.Identifier]struct{}
+	handler     g.InterceptHandler
+}
+
+func (grp *group) Deregister() {
+	grp.ix.mtx.Lock()
+	defer grp.ix.mtx.Unlock()
+	grp.ix.groups = slices.DeleteFunc(grp.ix.groups, func(other *group) bool { return other == grp })
+}
+
+// New creates a fake interceptor for the specified client type.
+func New(clientType g.ClientType) *Interceptor {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Interceptor{
+		ctx:     ctx,
+		cancel:  cancel,
+		client:  g.Client{Type: clientType},
+		mtx:     &sync.Mutex{},
+		headers: g.NewHeaders(),
+		next:    map[g.Direction]uint16{g.In: 1, g.Out: 1},
+		respond: map[g.Header][]Responder{},
+	}
+}
+
+// Close cancels the interceptor's context.
+func (ix *Interceptor) Close() {
+	ix.cancel()
+}
+
+// header returns the header for the specified identifier, assigning one if it does not exist.
+func (ix *Interceptor) header(id g.Identifier) g.Header {
+	ix.mtx.Lock()
+	defer ix.mtx.Unlock()
+	if header, ok := ix.headers.TryGet(id); ok {
+		return header
+	}
+	header := g.Header{Dir: id.Dir, Value: ix.next[id.Dir]}
+	ix.next[id.Dir]++
+	ix.headers.Add(id.Name, header)
+	return header
+}
+
+// NewPacket creates a packet for the specified identifier with the specified values.
+func (ix *Interceptor) NewPacket(id g.Identifier, values ...any) *g.Packet {
+	pkt := &g.Packet{Client: ix.client.Type, Header: ix.header(id)}
+	return pkt.Write(values...)
+}
+
+func (ix *Interceptor) Context() context.Context { return ix.ctx }
+func (ix *Interceptor) Client() g.Client         { return ix.client }
+func (ix *Interceptor) Headers() *g.Headers      { return ix.headers }
+
+func (ix *Interceptor) Send(id g.Identifier, values ...any) {
+	ix.SendPacket(ix.NewPacket(id, values...))
+}
+
+func (ix *Interceptor) SendPacket(pkt *g.Packet) {
+	ix.mtx.Lock()
+	ix.sent = append(ix.sent, pkt.Copy())
+	responders := slices.Clone(ix.respond[pkt.Header])
+	ix.mtx.Unlock()
+	for _, respond := range responders {
+		respond(ix, pkt.Copy())
+	}
+}
+
+// Respond registers a responder that is invoked with each packet sent with the specified identifier.
+func (ix *Interceptor) Respond(id g.Identifier, respond Responder) {
+	header := ix.header(id)
+	ix.mtx.Lock()
+	defer ix.mtx.Unlock()
+	ix.respond[header] = append(ix.respond[header], respond)
+}
+
+func (ix *Interceptor) Recv(identifiers ...g.Identifier) g.InlineInterceptor {
+	for _, id := range identifiers {
+		ix.header(id)
+	}
+	return g.NewInlineInterceptor(ix, identifiers)
+}
+
+func (ix *Interceptor) Register(grp *g.InterceptGroup) g.InterceptRef {
+	for id := range grp.Identifiers {
+		ix.header(id)
+	}
+	ix.mtx.Lock()
+	defer ix.mtx.Unlock()
+	reg := &group{ix: ix, identifiers: grp.Identifiers, handler: grp.Handler}
+	ix.groups = append(ix.groups, reg)
+	return reg
+}
+
+func (ix *Interceptor) Intercept(identifiers ...g.Identifier) g.InterceptBuilder {
+	return g.NewInterceptBuilder(ix, identifiers...)
+}
+
+func (ix *Interceptor) Initialized(g.EventHandler[g.InitArgs])  {}
+func (ix *Interceptor) Connected(g.EventHandler[g.ConnectArgs]) {}
+func (ix *Interceptor) Disconnected(g.VoidHandler)              {}
+
+// Sent returns the packets that have been sent.
+func (ix *Interceptor) Sent() []*g.Packet {
+	ix.mtx.Lock()
+	defer ix.mtx.Unlock()
+	return slices.Clone(ix.sent)
+}
+
+// SentTo returns the packets that have been sent with the specified identifier.
+func (ix *Interceptor) SentTo(id g.Identifier) (packets []*g.Packet) {
+	header := ix.header(id)
+	for _, pkt := range ix.Sent() {
+		if pkt.Header == header {
+			packets = append(packets, pkt)
+		}
+	}
+	return
+}
+
+// Resolve assigns headers to the specified identifiers ahead of time,
+// so that the header map is not modified while packets are being dispatched concurrently.
+func (ix *Interceptor) Resolve(identifiers ...g.Identifier) {
+	for _, id := range identifiers {
+		ix.header(id)
+	}
+}
+
+// Reset clears the sent packets.
+func (ix *Interceptor) Reset() {
+	ix.mtx.Lock()
+	defer ix.mtx.Unlock()
+	ix.sent = nil
+}
+
+// Dispatch dispatches a packet with the specified identifier and values to the registered intercept handlers.
+func (ix *Interceptor) Dispatch(id g.Identifier, values ...any) *g.Intercept {
+	return ix.DispatchPacket(ix.NewPacket(id, values...))
+}
+
+// DispatchLater dispatches a packet with the specified identifier and values after ResponseDelay.
+// It is used to respond to requests that are awaited only after they are sent.
+func (ix *Interceptor) DispatchLater(id g.Identifier, values ...any) {
+	pkt := ix.NewPacket(id, values...)
+	time.AfterFunc(ResponseDelay, func() { ix.DispatchPacket(pkt) })
+}
+
+// DispatchPacket dispatches the packet to the registered intercept handlers.
+func (ix *Interceptor) DispatchPacket(pkt *g.Packet) *g.Intercept {
+	var matched []*group
+	ix.mtx.Lock()
+	for _, grp := range ix.groups {
+		for id := range grp.identifiers {
+			if ix.headers.Is(pkt.Header, id) {
+				matched = append(matched, grp)
+				break
+			}
+		}
+	}
+	ix.mtx.Unlock()
+
+	e := g.NewIntercept(ix, pkt, 0, false)
+	for _, grp := range matched {
+		pkt.Pos = 0
+		grp.handler(e)
+	}
+	return e
+}
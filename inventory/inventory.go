@@ -0,0 +1,63 @@
+package inventory
+
+import (
+	"strconv"
+
+	g "xabbo.b7c.io/goearth"
+	"xabbo.b7c.io/goearth/room"
+)
+
+type ItemType string
+
+const (
+	Floor ItemType = "S"
+	Wall  ItemType = "I"
+)
+
+func (itemType *ItemType) Parse(p *g.Packet, pos *int) {
+	*itemType = ItemType(p.ReadStringPtr(pos))
+}
+
+// Item represents an inventory item.
+type Item struct {
+	ItemId g.Id
+	// Type represents the type of the item.
+	// May be "S" for "stuff" (floor item), or "I" for "item" (wall item).
+	Type                 ItemType
+	Id                   g.Id
+	Kind                 int
+	Category             int
+	Data                 room.StuffData
+	Recyclable           bool
+	Tradeable            bool
+	Groupable            bool
+	Sellable             bool
+	SecondsToExpiration  int
+	HasRentPeriodStarted bool
+	RoomId               g.Id
+	// SlotId and Extra are only available for floor items.
+	SlotId string
+	Extra  int
+}
+
+func (item Item) String() string {
+	return strconv.Itoa(item.Kind) + "(" + strconv.FormatInt(int64(item.ItemId), 10) + ")"
+}
+
+func (item *Item) Parse(p *g.Packet, pos *int) {
+	*item = Item{}
+	p.ReadPtr(pos, &item.ItemId, &item.Type, &item.Id, &item.Kind, &item.Category,
+		&item.Data, &item.Recyclable, &item.Tradeable, &item.Groupable, &item.Sellable,
+		&item.SecondsToExpiration, &item.HasRentPeriodStarted, &item.RoomId)
+	if item.Type == Floor {
+		p.ReadPtr(pos, &item.SlotId, &item.Extra)
+	}
+}
+
+// Fragment represents a fragment of the inventory.
+// The inventory is split into multiple fragments when it is loaded.
+type Fragment struct {
+	Total int
+	Index int
+	Items []Item
+}
@@ -0,0 +1,50 @@
+package inventory
+
+import g "xabbo.b7c.io/goearth"
+
+// Args holds the arguments for inventory events involving a list of items.
+type Args struct {
+	Items []Item
+}
+
+// ItemArgs holds the arguments for inventory events involving a single item.
+type ItemArgs struct {
+	Item Item
+}
+
+// ItemUpdateArgs holds the arguments for inventory item update events.
+type ItemUpdateArgs struct {
+	Pre  Item // Pre is the previous state of the item before the update.
+	Item Item // Item is the current state of the item after the update.
+}
+
+// Loaded registers an event handler that is invoked when the inventory is fully loaded.
+func (mgr *Manager) Loaded(handlers ...g.EventHandler[Args]) *g.Event[Args] {
+	mgr.loaded.Register(handlers...)
+	return &mgr.loaded
+}
+
+// Invalidated registers an event handler that is invoked when the inventory is invalidated by the server,
+// indicating that it should be reloaded.
+func (mgr *Manager) Invalidated(handlers ...g.VoidHandler) *g.VoidEvent {
+	mgr.invalidated.Register(handlers...)
+	return &mgr.invalidated
+}
+
+// ItemAdded registers an event handler that is invoked when an item is added to the inventory.
+func (mgr *Manager) ItemAdded(handlers ...g.EventHandler[ItemArgs]) *g.Event[ItemArgs] {
+	mgr.itemAdded.Register(handlers...)
+	return &mgr.itemAdded
+}
+
+// ItemUpdated registers an event handler that is invoked when an item in the inventory is updated.
+func (mgr *Manager) ItemUpdated(handlers ...g.EventHandler[ItemUpdateArgs]) *g.Event[ItemUpdateArgs] {
+	mgr.itemUpdated.Register(handlers...)
+	return &mgr.itemUpdated
+}
+
+// ItemRemoved registers an event handler that is invoked when an item is removed from the inventory.
+func (mgr *Manager) ItemRemoved(handlers ...g.EventHandler[ItemArgs]) *g.Event[ItemArgs] {
+	mgr.itemRemoved.Register(handlers...)
+	return &mgr.itemRemoved
+}
@@ -0,0 +1,192 @@
+package inventory
+
+import (
+	"context"
+	"sync"
+
+	g "xabbo.b7c.io/goearth"
+	"xabbo.b7c.io/goearth/in"
+	"xabbo.b7c.io/goearth/internal/debug"
+	"xabbo.b7c.io/goearth/out"
+)
+
+var dbg = debug.NewLogger("[inventory]")
+
+// Manager tracks the state of the inventory.
+type Manager struct {
+	ix          g.Interceptor
+	loaded      g.Event[Args]
+	invalidated g.VoidEvent
+	itemAdded   g.Event[ItemArgs]
+	itemUpdated g.Event[ItemUpdateArgs]
+	itemRemoved g.Event[ItemArgs]
+
+	mtx       *sync.RWMutex
+	isLoaded  bool
+	items     map[g.Id]Item
+	fragTotal int
+	fragments map[int][]Item
+}
+
+// NewManager creates a new inventory Manager using the provided extension.
+func NewManager(ix g.Interceptor) *Manager {
+	mgr := &Manager{
+		ix:    ix,
+		mtx:   &sync.RWMutex{},
+		items: map[g.Id]Item{},
+	}
+	ix.Intercept(in.FurniList).With(mgr.handleFurniList)
+	ix.Intercept(in.FurniListAddOrUpdate).With(mgr.handleFurniListAddOrUpdate)
+	ix.Intercept(in.FurniListRemove).With(mgr.handleFurniListRemove)
+	ix.Intercept(in.FurniListInvalidate).With(mgr.handleFurniListInvalidate)
+	return mgr
+}
+
+// IsLoaded returns whether the inventory has been fully loaded and has not since been invalidated.
+func (mgr *Manager) IsLoaded() bool {
+	mgr.mtx.RLock()
+	defer mgr.mtx.RUnlock()
+	return mgr.isLoaded
+}
+
+// Item gets the item with the specified item ID.
+func (mgr *Manager) Item(id g.Id) *Item {
+	mgr.mtx.RLock()
+	defer mgr.mtx.RUnlock()
+	if item, ok := mgr.items[id]; ok {
+		return &item
+	} else {
+		return nil
+	}
+}
+
+// Items iterates over all inventory items.
+func (mgr *Manager) Items(yield func(item Item) bool) {
+	mgr.mtx.RLock()
+	for _, item := range mgr.items {
+		mgr.mtx.RUnlock()
+		if !yield(item) {
+			return
+		}
+		mgr.mtx.RLock()
+	}
+	mgr.mtx.RUnlock()
+}
+
+// ItemCount returns the number of items in the inventory.
+func (mgr *Manager) ItemCount() int {
+	mgr.mtx.RLock()
+	defer mgr.mtx.RUnlock()
+	return len(mgr.items)
+}
+
+// Load requests the inventory from the server and waits until all of its fragments have been received.
+// Returns the cause of the context's cancellation if it is done before the inventory is loaded.
+func (mgr *Manager) Load(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	ch := mgr.loaded.Chan(ctx)
+	dbg.Printf("requesting inventory")
+	mgr.ix.Send(out.RequestFurniInventory)
+	select {
+	case <-ch:
+		return nil
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
+
+// addFragment adds a fragment to the inventory and
+// returns the complete list of items once all fragments have been received.
+func (mgr *Manager) addFragment(frag Fragment) (items []Item, complete bool) {
+	mgr.mtx.Lock()
+	defer mgr.mtx.Unlock()
+
+	if frag.Index == 0 || frag.Total != mgr.fragTotal || mgr.fragments == nil {
+		mgr.fragTotal = frag.Total
+		mgr.fragments = map[int][]Item{}
+	}
+	mgr.fragments[frag.Index] = frag.Items
+	dbg.Printf("received fragment %d/%d (%d items)", frag.Index+1, frag.Total, len(frag.Items))
+
+	if len(mgr.fragments) < mgr.fragTotal {
+		return
+	}
+
+	mgr.items = map[g.Id]Item{}
+	for i := range mgr.fragTotal {
+		for _, item := range mgr.fragments[i] {
+			mgr.items[item.ItemId] = item
+			items = append(items, item)
+		}
+	}
+	mgr.fragments = nil
+	mgr.isLoaded = true
+	dbg.Printf("loaded %d item(s)", len(items))
+
+	return items, true
+}
+
+func (mgr *Manager) addOrUpdateItem(item Item) (pre Item, updated bool) {
+	mgr.mtx.Lock()
+	defer mgr.mtx.Unlock()
+
+	pre, updated = mgr.items[item.ItemId]
+	mgr.items[item.ItemId] = item
+	return
+}
+
+func (mgr *Manager) removeItem(id g.Id) (item Item, ok bool) {
+	mgr.mtx.Lock()
+	defer mgr.mtx.Unlock()
+
+	if item, ok = mgr.items[id]; ok {
+		delete(mgr.items, id)
+		dbg.Printf("removed item (ID: %d)", id)
+	} else {
+		dbg.Printf("failed to find item to remove (ID: %d)", id)
+	}
+
+	return
+}
+
+// handlers
+
+func (mgr *Manager) handleFurniList(e *g.Intercept) {
+	var frag Fragment
+	e.Packet.Read(&frag)
+
+	if items, complete := mgr.addFragment(frag); complete {
+		mgr.loaded.Dispatch(Args{items})
+	}
+}
+
+func (mgr *Manager) handleFurniListAddOrUpdate(e *g.Intercept) {
+	var item Item
+	e.Packet.Read(&item)
+
+	if pre, updated := mgr.addOrUpdateItem(item); updated {
+		mgr.itemUpdated.Dispatch(ItemUpdateArgs{Pre: pre, Item: item})
+	} else {
+		mgr.itemAdded.Dispatch(ItemArgs{item})
+	}
+}
+
+func (mgr *Manager) handleFurniListRemove(e *g.Intercept) {
+	var id g.Id
+	e.Packet.Read(&id)
+
+	if item, ok := mgr.removeItem(id); ok {
+		mgr.itemRemoved.Dispatch(ItemArgs{item})
+	}
+}
+
+func (mgr *Manager) handleFurniListInvalidate(e *g.Intercept) {
+	mgr.mtx.Lock()
+	mgr.isLoaded = false
+	mgr.mtx.Unlock()
+
+	dbg.Printf("inventory invalidated")
+	mgr.invalidated.Dispatch()
+}
@@ -0,0 +1,42 @@
+package inventory
+
+import (
+	"reflect"
+	"testing"
+
+	g "xabbo.b7c.io/goearth"
+	"xabbo.b7c.io/goearth/room"
+)
+
+func TestFragment(t *testing.T) {
+	pkt := &g.Packet{Client: g.Flash, Header: g.Header{Dir: g.In}}
+	pkt.Write(2, 1, 2)
+	pkt.Write(1, "S", 10, 100, 1, room.StuffData{Value: "1"},
+		true, true, false, true, -1, false, 0, "", 0)
+	pkt.Write(2, "I", 20, 200, 1, room.StuffData{Format: room.EmptyFormat},
+		false, true, true, false, -1, false, 0)
+
+	var frag Fragment
+	pkt.Pos = 0
+	pkt.Read(&frag)
+
+	if pkt.Pos != pkt.Length() {
+		t.Fatal("parser failed to read entire packet")
+	}
+
+	expected := Fragment{
+		Total: 2,
+		Index: 1,
+		Items: []Item{
+			{ItemId: 1, Type: Floor, Id: 10, Kind: 100, Category: 1,
+				Data:       room.StuffData{Value: "1"},
+				Recyclable: true, Tradeable: true, Sellable: true, SecondsToExpiration: -1},
+			{ItemId: 2, Type: Wall, Id: 20, Kind: 200, Category: 1,
+				Data:      room.StuffData{Format: room.EmptyFormat},
+				Tradeable: true, Groupable: true, SecondsToExpiration: -1},
+		},
+	}
+	if !reflect.DeepEqual(expected, frag) {
+		t.Fatalf("incorrect fragment, expected: %+v, actual: %+v", expected, frag)
+	}
+}
@@ -0,0 +1,79 @@
+package profile
+
+import (
+	"strconv"
+
+	g "xabbo.b7c.io/goearth"
+)
+
+// Profile holds the information of the current user.
+type Profile struct {
+	Id                      g.Id
+	Name                    string
+	Figure                  string
+	Gender                  string
+	Motto                   string
+	RealName                string
+	DirectMail              bool
+	RespectReceived         int
+	RespectLeft             int
+	PetRespectLeft          int
+	StreamPublishingAllowed bool
+	LastAccessDate          string
+	NameChangeAllowed       bool
+	AccountSafetyLocked     bool
+}
+
+// Rights holds the subscription and security levels of the current user.
+type Rights struct {
+	ClubLevel     int
+	SecurityLevel int
+	Ambassador    bool
+}
+
+// Activity point types.
+const (
+	Duckets  = 0
+	Diamonds = 5
+)
+
+// ActivityPoints maps activity point types to their amounts.
+type ActivityPoints map[int]int
+
+func (points *ActivityPoints) Parse(p *g.Packet, pos *int) {
+	var n g.Length
+	p.ReadPtr(pos, &n)
+	*points = make(ActivityPoints, n)
+	for range n {
+		var kind, amount int
+		p.ReadPtr(pos, &kind, &amount)
+		(*points)[kind] = amount
+	}
+}
+
+// Credits represents the user's credit balance.
+// It is sent as a string by the server.
+type Credits int
+
+func (credits *Credits) Parse(p *g.Packet, pos *int) {
+	s := p.ReadStringPtr(pos)
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		dbg.Printf("WARNING: invalid credit balance: %q", s)
+		return
+	}
+	*credits = Credits(f)
+}
+
+// Badge represents a badge owned by the current user.
+type Badge struct {
+	Id   int
+	Code string
+}
+
+// BadgeFragment represents a fragment of the user's badges.
+type BadgeFragment struct {
+	Total  int
+	Index  int
+	Badges []Badge
+}
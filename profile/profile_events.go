@@ -0,0 +1,19 @@
+package profile
+
+import g "xabbo.b7c.io/goearth"
+
+// Args contains the event arguments for profile events.
+type Args struct {
+	Profile        Profile
+	Rights         Rights
+	Credits        int
+	ActivityPoints ActivityPoints
+	Badges         []Badge
+}
+
+// Updated registers an event handler that is invoked when the user's profile,
+// rights, currencies or badges are updated.
+func (mgr *Manager) Updated(handlers ...g.EventHandler[Args]) *g.Event[Args] {
+	mgr.updated.Register(handlers...)
+	return &mgr.updated
+}
@@ -0,0 +1,152 @@
+package profile
+
+import (
+	"maps"
+	"slices"
+
+	g "xabbo.b7c.io/goearth"
+	"xabbo.b7c.io/goearth/in"
+	"xabbo.b7c.io/goearth/internal/debug"
+	"xabbo.b7c.io/goearth/out"
+)
+
+var dbg = debug.NewLogger("[profile]")
+
+// Manager tracks the state of the user's profile.
+type Manager struct {
+	ix               g.Interceptor
+	requestOnConnect bool
+
+	updated g.Event[Args]
+
+	Profile
+	Rights         Rights
+	Credits        int
+	ActivityPoints ActivityPoints
+	Badges         []Badge
+
+	badgeTotal     int
+	badgeFragments map[int][]Badge
+}
+
+func NewManager(ix g.Interceptor) *Manager {
+	mgr := &Manager{ix: ix, ActivityPoints: ActivityPoints{}}
+	ix.Initialized(mgr.onInitialized)
+	ix.Connected(mgr.onConnected)
+	ix.Disconnected(mgr.onDisconnected)
+	ix.Intercept(in.UserObject).With(mgr.handleUserObject)
+	ix.Intercept(in.UserRights).With(mgr.handleUserRights)
+	ix.Intercept(in.CreditBalance).With(mgr.handleCreditBalance)
+	ix.Intercept(in.ActivityPoints).With(mgr.handleActivityPoints)
+	ix.Intercept(in.HabboActivityPointNotification).With(mgr.handleHabboActivityPointNotification)
+	ix.Intercept(in.Badges).With(mgr.handleBadges)
+	return mgr
+}
+
+// Duckets returns the user's duckets.
+func (mgr *Manager) Duckets() int {
+	return mgr.ActivityPoints[Duckets]
+}
+
+// Diamonds returns the user's diamonds.
+func (mgr *Manager) Diamonds() int {
+	return mgr.ActivityPoints[Diamonds]
+}
+
+func (mgr *Manager) onInitialized(e g.InitArgs) {
+	if e.Connected {
+		// game is already connected, assume safe to send request
+		mgr.requestOnConnect = true
+		dbg.Println("game is connected, will request on connect")
+	}
+	// otherwise wait to receive the packets naturally
+}
+
+func (mgr *Manager) onConnected(e g.ConnectArgs) {
+	if mgr.requestOnConnect {
+		mgr.ix.Send(out.InfoRetrieve)
+		mgr.ix.Send(out.GetCreditsInfo)
+		mgr.ix.Send(out.GetBadges)
+		dbg.Println("requested profile")
+	}
+}
+
+func (mgr *Manager) onDisconnected() {
+	mgr.requestOnConnect = false
+}
+
+func (mgr *Manager) dispatchUpdated() {
+	mgr.updated.Dispatch(Args{
+		Profile:        mgr.Profile,
+		Rights:         mgr.Rights,
+		Credits:        mgr.Credits,
+		ActivityPoints: maps.Clone(mgr.ActivityPoints),
+		Badges:         slices.Clone(mgr.Badges),
+	})
+}
+
+// handlers
+
+func (mgr *Manager) handleUserObject(e *g.Intercept) {
+	e.Packet.Read(&mgr.Profile)
+	mgr.dispatchUpdated()
+
+	dbg.Printf("received user profile for %q", mgr.Profile.Name)
+}
+
+func (mgr *Manager) handleUserRights(e *g.Intercept) {
+	e.Packet.Read(&mgr.Rights)
+	mgr.dispatchUpdated()
+
+	dbg.Printf("received user rights (club level: %d, security level: %d)",
+		mgr.Rights.ClubLevel, mgr.Rights.SecurityLevel)
+}
+
+func (mgr *Manager) handleCreditBalance(e *g.Intercept) {
+	var credits Credits
+	e.Packet.Read(&credits)
+	mgr.Credits = int(credits)
+	mgr.dispatchUpdated()
+
+	dbg.Printf("received credit balance: %d", mgr.Credits)
+}
+
+func (mgr *Manager) handleActivityPoints(e *g.Intercept) {
+	e.Packet.Read(&mgr.ActivityPoints)
+	mgr.dispatchUpdated()
+
+	dbg.Printf("received activity points: %v", mgr.ActivityPoints)
+}
+
+func (mgr *Manager) handleHabboActivityPointNotification(e *g.Intercept) {
+	var amount, change, kind int
+	e.Packet.Read(&amount, &change, &kind)
+	mgr.ActivityPoints[kind] = amount
+	mgr.dispatchUpdated()
+
+	dbg.Printf("activity points updated (type: %d, amount: %d, change: %d)", kind, amount, change)
+}
+
+func (mgr *Manager) handleBadges(e *g.Intercept) {
+	var frag BadgeFragment
+	e.Packet.Read(&frag)
+
+	if frag.Index == 0 || frag.Total != mgr.badgeTotal || mgr.badgeFragments == nil {
+		mgr.badgeTotal = frag.Total
+		mgr.badgeFragments = map[int][]Badge{}
+	}
+	mgr.badgeFragments[frag.Index] = frag.Badges
+	if len(mgr.badgeFragments) < mgr.badgeTotal {
+		return
+	}
+
+	badges := []Badge{}
+	for i := range mgr.badgeTotal {
+		badges = append(badges, mgr.badgeFragments[i]...)
+	}
+	mgr.badgeFragments = nil
+	mgr.Badges = badges
+	mgr.dispatchUpdated()
+
+	dbg.Printf("received %d badge(s)", len(badges))
+}
@@ -0,0 +1,51 @@
+package profile
+
+import (
+	"reflect"
+	"testing"
+
+	g "xabbo.b7c.io/goearth"
+)
+
+func TestProfile(t *testing.T) {
+	pkt := &g.Packet{Client: g.Flash, Header: g.Header{Dir: g.In}}
+	pkt.Write(1, "name", "hd-180-1", "M", "motto", "", false, 10, 3, 3, true, "01-01-2024 00:00:00", false, false)
+
+	var profile Profile
+	pkt.Pos = 0
+	pkt.Read(&profile)
+
+	if pkt.Pos != pkt.Length() {
+		t.Fatal("parser failed to read entire packet")
+	}
+
+	expected := Profile{
+		Id: 1, Name: "name", Figure: "hd-180-1", Gender: "M", Motto: "motto",
+		RespectReceived: 10, RespectLeft: 3, PetRespectLeft: 3,
+		StreamPublishingAllowed: true, LastAccessDate: "01-01-2024 00:00:00",
+	}
+	if !reflect.DeepEqual(expected, profile) {
+		t.Fatalf("incorrect profile, expected: %+v, actual: %+v", expected, profile)
+	}
+}
+
+func TestCurrencies(t *testing.T) {
+	pkt := &g.Packet{Client: g.Flash, Header: g.Header{Dir: g.In}}
+	pkt.Write("150.0", 2, Duckets, 300, Diamonds, 20)
+
+	var credits Credits
+	var points ActivityPoints
+	pkt.Pos = 0
+	pkt.Read(&credits, &points)
+
+	if pkt.Pos != pkt.Length() {
+		t.Fatal("parser failed to read entire packet")
+	}
+	if credits != 150 {
+		t.Fatalf("incorrect credits, expected: %d, actual: %d", 150, credits)
+	}
+	expected := ActivityPoints{Duckets: 300, Diamonds: 20}
+	if !reflect.DeepEqual(expected, points) {
+		t.Fatalf("incorrect activity points, expected: %v, actual: %v", expected, points)
+	}
+}
@@ -0,0 +1,365 @@
+package room
+
+import (
+	"fmt"
+	"strconv"
+
+	g "xabbo.b7c.io/goearth"
+)
+
+// Info contains information about a room.
+type Info struct {
+	Id                 g.Id
+	Name               string
+	OwnerId            g.Id
+	Owner              string
+	Door               int
+	UserCount          int
+	MaxUsers           int
+	Description        string
+	Trading            int
+	Score              int
+	Ranking            int
+	CategoryId         int
+	Tags               []string
+	Flags              int
+	OfficialRoomPicRef string
+	GroupId            g.Id
+	GroupName          string
+	GroupBadge         string
+	EventName          string
+	EventDescription   string
+	EventMinutesLeft   int
+}
+
+// Room info flags.
+const (
+	FlagOfficialRoomPic = 1 << iota
+	FlagGroup
+	FlagEvent
+	FlagShowOwner
+	FlagAllowPets
+	FlagShowRoomAd
+)
+
+func (info *Info) Parse(p *g.Packet, pos *int) {
+	*info = Info{}
+	p.ReadPtr(pos, &info.Id, &info.Name, &info.OwnerId, &info.Owner,
+		&info.Door, &info.UserCount, &info.MaxUsers, &info.Description,
+		&info.Trading, &info.Score, &info.Ranking, &info.CategoryId,
+		&info.Tags, &info.Flags)
+	if info.Flags&FlagOfficialRoomPic != 0 {
+		p.ReadPtr(pos, &info.OfficialRoomPicRef)
+	}
+	if info.Flags&FlagGroup != 0 {
+		p.ReadPtr(pos, &info.GroupId, &info.GroupName, &info.GroupBadge)
+	}
+	if info.Flags&FlagEvent != 0 {
+		p.ReadPtr(pos, &info.EventName, &info.EventDescription, &info.EventMinutesLeft)
+	}
+}
+
+// Object represents a floor item in a room.
+type Object struct {
+	Id                  g.Id
+	Kind                int
+	X, Y                int
+	Direction           int
+	Z                   float64
+	Height              float64
+	Extra               int
+	Data                StuffData
+	SecondsToExpiration int
+	Usage               int
+	OwnerId             g.Id
+	OwnerName           string
+	// StaticClass holds the class name of the object if its Kind is negative.
+	StaticClass string
+}
+
+func (obj Object) String() string {
+	return strconv.Itoa(obj.Kind) + "(" + strconv.FormatInt(int64(obj.Id), 10) + ")"
+}
+
+func (obj *Object) Parse(p *g.Packet, pos *int) {
+	*obj = Object{}
+	p.ReadPtr(pos, &obj.Id, &obj.Kind, &obj.X, &obj.Y, &obj.Direction,
+		&obj.Z, &obj.Height, &obj.Extra, &obj.Data,
+		&obj.SecondsToExpiration, &obj.Usage, &obj.OwnerId)
+	if obj.Kind < 0 {
+		p.ReadPtr(pos, &obj.StaticClass)
+	}
+}
+
+// Objects represents a list of floor items with their owner names.
+type Objects []Object
+
+func (objs *Objects) Parse(p *g.Packet, pos *int) {
+	owners := parseOwners(p, pos)
+	var list []Object
+	p.ReadPtr(pos, &list)
+	for i := range list {
+		list[i].OwnerName = owners[list[i].OwnerId]
+	}
+	*objs = list
+}
+
+// Item represents a wall item in a room.
+type Item struct {
+	Id                  g.Id
+	Kind                int
+	Location            string
+	Data                string
+	SecondsToExpiration int
+	Usage               int
+	OwnerId             g.Id
+	OwnerName           string
+}
+
+func (item Item) String() string {
+	return strconv.Itoa(item.Kind) + "(" + strconv.FormatInt(int64(item.Id), 10) + ")"
+}
+
+func (item *Item) Parse(p *g.Packet, pos *int) {
+	// The item ID is a string.
+	strId := p.ReadStringPtr(pos)
+	id, err := strconv.ParseInt(strId, 10, 64)
+	if err != nil {
+		panic(fmt.Errorf("invalid item ID: %q", strId))
+	}
+
+	*item = Item{Id: g.Id(id)}
+	p.ReadPtr(pos, &item.Kind, &item.Location, &item.Data,
+		&item.SecondsToExpiration, &item.Usage, &item.OwnerId)
+}
+
+// Items represents a list of wall items with their owner names.
+type Items []Item
+
+func (items *Items) Parse(p *g.Packet, pos *int) {
+	owners := parseOwners(p, pos)
+	var list []Item
+	p.ReadPtr(pos, &list)
+	for i := range list {
+		list[i].OwnerName = owners[list[i].OwnerId]
+	}
+	*items = list
+}
+
+// parseOwners parses the owner ID -> name map that precedes a list of furni.
+func parseOwners(p *g.Packet, pos *int) map[g.Id]string {
+	var n g.Length
+	p.ReadPtr(pos, &n)
+	owners := make(map[g.Id]string, n)
+	for range n {
+		var id g.Id
+		var name string
+		p.ReadPtr(pos, &id, &name)
+		owners[id] = name
+	}
+	return owners
+}
+
+type SlideObjectBundle struct {
+	From, To      Point
+	Objects       []SlideObject
+	RollerId      g.Id
+	SlideMoveType SlideMoveType
+	Entity        SlideObject
+}
+
+func (bundle *SlideObjectBundle) Parse(p *g.Packet, pos *int) {
+	*bundle = SlideObjectBundle{}
+	p.ReadPtr(pos, &bundle.From, &bundle.To, &bundle.Objects, &bundle.RollerId)
+	if *pos < p.Length() {
+		p.ReadPtr(pos, &bundle.SlideMoveType, &bundle.Entity)
+	}
+}
+
+type SlideObject struct {
+	Id         g.Id
+	FromZ, ToZ float64
+}
+
+type SlideMoveType int
+
+const (
+	SlideMoveTypeNone SlideMoveType = iota
+	SlideMoveTypeMove
+	SlideMoveTypeSlide
+)
+
+func (slideType *SlideMoveType) Parse(p *g.Packet, pos *int) {
+	*slideType = SlideMoveType(p.ReadIntPtr(pos))
+}
+
+type EntityType int
+
+const (
+	User EntityType = iota + 1
+	Pet
+	PublicBot
+	PrivateBot
+)
+
+func (entityType EntityType) String() string {
+	switch entityType {
+	case User:
+		return "user"
+	case Pet:
+		return "pet"
+	case PublicBot:
+		return "public bot"
+	case PrivateBot:
+		return "private bot"
+	default:
+		return strconv.Itoa(int(entityType))
+	}
+}
+
+func (entityType *EntityType) Parse(p *g.Packet, pos *int) {
+	*entityType = EntityType(p.ReadIntPtr(pos))
+}
+
+func (entityType EntityType) Compose(p *g.Packet, pos *int) {
+	p.WriteIntPtr(pos, int(entityType))
+}
+
+// Point represents 2-dimensional coordinates in a room.
+type Point struct {
+	X, Y int
+}
+
+func (pt Point) String() string {
+	return strconv.Itoa(pt.X) + ", " + strconv.Itoa(pt.Y)
+}
+
+// ToTile converts the Point to a Tile with Z = 0.
+func (pt Point) ToTile() Tile {
+	return Tile{pt.X, pt.Y, 0}
+}
+
+// Tile represents 3-dimensional coordinates in a room.
+type Tile struct {
+	X, Y int
+	Z    float64
+}
+
+func (tile Tile) String() string {
+	return strconv.Itoa(tile.X) + ", " + strconv.Itoa(tile.Y) + ", " + strconv.FormatFloat(tile.Z, 'f', 2, 64)
+}
+
+// ToPoint converts the Tile to a Point.
+func (tile Tile) ToPoint() Point {
+	return Point{tile.X, tile.Y}
+}
+
+// Entity represents a user, pet or bot in a room.
+type Entity struct {
+	Id     g.Id
+	Name   string
+	Motto  string
+	Figure string
+	Index  int
+	Tile
+	Dir     int
+	HeadDir int
+	Type    EntityType
+	Action  string
+
+	// Gender is available for users and private bots.
+	Gender string
+	// Group information is available for users.
+	GroupId          g.Id
+	GroupStatus      int
+	GroupName        string
+	FigureExtra      string
+	AchievementScore int
+	IsModerator      bool
+
+	// Pet information.
+	Breed                 int
+	RarityLevel           int
+	HasSaddle             bool
+	IsRiding              bool
+	CanBreed              bool
+	CanHarvest            bool
+	CanRevive             bool
+	HasBreedingPermission bool
+	Level                 int
+	Posture               string
+
+	// OwnerId and OwnerName are available for pets and private bots.
+	OwnerId   g.Id
+	OwnerName string
+	// Skills are available for private bots.
+	Skills []int16
+}
+
+func (ent Entity) String() string {
+	return ent.Name
+}
+
+func (ent *Entity) Parse(p *g.Packet, pos *int) {
+	*ent = Entity{}
+	p.ReadPtr(pos, &ent.Id, &ent.Name, &ent.Motto, &ent.Figure,
+		&ent.Index, &ent.Tile, &ent.Dir, &ent.Type)
+	ent.HeadDir = ent.Dir
+
+	switch ent.Type {
+	case User:
+		p.ReadPtr(pos, &ent.Gender, &ent.GroupId, &ent.GroupStatus, &ent.GroupName,
+			&ent.FigureExtra, &ent.AchievementScore, &ent.IsModerator)
+	case Pet:
+		p.ReadPtr(pos, &ent.Breed, &ent.OwnerId, &ent.OwnerName, &ent.RarityLevel,
+			&ent.HasSaddle, &ent.IsRiding, &ent.CanBreed, &ent.CanHarvest,
+			&ent.CanRevive, &ent.HasBreedingPermission, &ent.Level, &ent.Posture)
+	case PublicBot:
+	case PrivateBot:
+		p.ReadPtr(pos, &ent.Gender, &ent.OwnerId, &ent.OwnerName, &ent.Skills)
+	default:
+		panic(fmt.Errorf("unknown entity type: %d", ent.Type))
+	}
+}
+
+// EntityStatus represents a status update of an entity in a room.
+type EntityStatus struct {
+	Index int
+	Tile
+	HeadDir, BodyDir int
+	Action           string
+}
+
+// EntityChange represents a change to an entity's figure, gender or motto.
+type EntityChange struct {
+	Index            int
+	Figure           string
+	Gender           string
+	Motto            string
+	AchievementScore int
+}
+
+// Chat represents a chat message sent by an entity.
+type Chat struct {
+	Index      int
+	Message    string
+	Gesture    int
+	Style      int
+	Links      []ChatLink
+	TrackingId int
+}
+
+// ChatLink represents a link in a chat message.
+type ChatLink struct {
+	Url     string
+	Label   string
+	Visible bool
+}
+
+type ChatType int
+
+const (
+	Talk ChatType = iota + 1
+	Whisper
+	Shout
+)
@@ -0,0 +1,187 @@
+package room
+
+import g "xabbo.b7c.io/goearth"
+
+// Args hold the arguments for room events.
+type Args struct {
+	Id   g.Id
+	Info *Info
+}
+
+// ObjectArgs holds the arguments for floor item events involving a single item.
+type ObjectArgs struct {
+	Object Object
+}
+
+// ObjectUpdateArgs holds the arguments for floor item update events.
+type ObjectUpdateArgs struct {
+	Pre    Object // Prev is the previous state of the object before the update.
+	Object Object // Cur is the current state of the object after the update.
+}
+
+// ObjectArgs holds the arguments for floor item events involving a list of items.
+type ObjectsArgs struct {
+	Objects []Object
+}
+
+// SlideArgs holds the arguments for floor item and entity slide events.
+type SlideArgs struct {
+	From, To     Point
+	ObjectSlides []SlideObjectArgs
+	// Source contains the object that caused the slide, if it is available.
+	Source        *Object
+	SlideMoveType SlideMoveType
+	// EntitySlide contains arguments for an entity slide event, if an entity is involved in this event.
+	EntitySlide *SlideEntityArgs
+}
+
+// SlideObjectArgs holds the arguments for floor item slide events.
+type SlideObjectArgs struct {
+	// Object contains the state of the object after the slide update.
+	Object   Object
+	From, To Tile
+}
+
+type SlideEntityArgs struct {
+	// Entity contains the state of the entity after the slide update.
+	Entity   Entity
+	From, To Tile
+}
+
+// ItemArgs holds the arguments for wall item events involing a single item.
+type ItemArgs struct {
+	Item Item
+}
+
+// ItemUpdateArgs holds the arguments for wall item update events.
+type ItemUpdateArgs struct {
+	Pre  Item // Pre is the previous state of the item before the update.
+	Item Item // Item is the current state of the item after the update.
+}
+
+// ItemsArgs holds the arguments for wall item events involing a list of items.
+type ItemsArgs struct {
+	Items []Item
+}
+
+// EntityArgs holds the arguments for events involving a single entity.
+type EntityArgs struct {
+	Entity Entity
+}
+
+// EntityUpdateArgs holds the arguments for entity update events.
+type EntityUpdateArgs struct {
+	Pre    Entity // Prev is the previous state of the entity before the update.
+	Entity Entity // Cur is the current state of the entity after the update.
+}
+
+// EntitiesArgs holds the arguments for events involving a list of entities.
+type EntitiesArgs struct {
+	Entered  bool
+	Entities []Entity
+}
+
+// EntityChat holds the arguments for chat events.
+type EntityChatArgs struct {
+	EntityArgs
+	Type    ChatType
+	Message string
+	Gesture int
+	Style   int
+}
+
+// Entered registers an event handler that is invoked when the user enters a room.
+func (mgr *Manager) Entered(handlers ...g.EventHandler[Args]) *g.Event[Args] {
+	mgr.entered.Register(handlers...)
+	return &mgr.entered
+}
+
+func (mgr *Manager) RightsUpdated(handlers ...g.VoidHandler) *g.VoidEvent {
+	mgr.rightsUpdated.Register(handlers...)
+	return &mgr.rightsUpdated
+}
+
+// ObjectsLoaded registers an event handler that is invoked when floor items are loaded.
+func (mgr *Manager) ObjectsLoaded(handlers ...g.EventHandler[ObjectsArgs]) *g.Event[ObjectsArgs] {
+	mgr.objectsLoaded.Register(handlers...)
+	return &mgr.objectsLoaded
+}
+
+// ObjectAdded registers an event handler that is invoked when a floor item is added to the room.
+func (mgr *Manager) ObjectAdded(handlers ...g.EventHandler[ObjectArgs]) *g.Event[ObjectArgs] {
+	mgr.objectAdded.Register(handlers...)
+	return &mgr.objectAdded
+}
+
+// ObjectUpdated registers an event handler that is invoked when a floor item is updated in the room.
+func (mgr *Manager) ObjectUpdated(handlers ...g.EventHandler[ObjectUpdateArgs]) *g.Event[ObjectUpdateArgs] {
+	mgr.objectUpdated.Register(handlers...)
+	return &mgr.objectUpdated
+}
+
+// ObjectRemoved registers an event handler that is invoked when a floor item is removed from the room.
+func (mgr *Manager) ObjectRemoved(handlers ...g.EventHandler[ObjectArgs]) *g.Event[ObjectArgs] {
+	mgr.objectRemoved.Register(handlers...)
+	return &mgr.objectRemoved
+}
+
+// Slide registers an event handler that is invoked when floor items or an entity slides, e.g. along a roller.
+func (mgr *Manager) Slide(handlers ...g.EventHandler[SlideArgs]) *g.Event[SlideArgs] {
+	mgr.slide.Register(handlers...)
+	return &mgr.slide
+}
+
+// ItemsLoaded registers an event handler that is invoked when wall items are loaded.
+func (mgr *Manager) ItemsLoaded(handlers ...g.EventHandler[ItemsArgs]) *g.Event[ItemsArgs] {
+	mgr.itemsLoaded.Register(handlers...)
+	return &mgr.itemsLoaded
+}
+
+// ItemAdded registers an event handler that is invoked when a wall item is added to the room.
+func (mgr *Manager) ItemAdded(handlers ...g.EventHandler[ItemArgs]) *g.Event[ItemArgs] {
+	mgr.itemAdded.Register(handlers...)
+	return &mgr.itemAdded
+}
+
+// ItemUpdated registers an event handler that is invoked when a wall item is updated in the room.
+func (mgr *Manager) ItemUpdated(handlers ...g.EventHandler[ItemUpdateArgs]) *g.Event[ItemUpdateArgs] {
+	mgr.itemUpdated.Register(handlers...)
+	return &mgr.itemUpdated
+}
+
+// ItemRemoved registers an event handler that is invoked when an item is removed from the room.
+func (mgr *Manager) ItemRemoved(handlers ...g.EventHandler[ItemArgs]) *g.Event[ItemArgs] {
+	mgr.itemRemoved.Register(handlers...)
+	return &mgr.itemRemoved
+}
+
+// EntitiesAdded registers an event handler that is invoked when entities are loaded or enter the room.
+// The Entered flag on the EntitiesArgs indicates whether the entity entered the room.
+// If not, the entities were already in the room and are being loaded.
+func (mgr *Manager) EntitiesAdded(handlers ...g.EventHandler[EntitiesArgs]) *g.Event[EntitiesArgs] {
+	mgr.entitiesAdded.Register(handlers...)
+	return &mgr.entitiesAdded
+}
+
+func (mgr *Manager) EntityUpdated(handlers ...g.EventHandler[EntityUpdateArgs]) *g.Event[EntityUpdateArgs] {
+	mgr.entityUpdated.Register(handlers...)
+	return &mgr.entityUpdated
+}
+
+// EntityChat registers an event handler that is invoked when an entity sends a chat message.
+func (mgr *Manager) EntityChat(handlers ...g.EventHandler[EntityChatArgs]) *g.Event[EntityChatArgs] {
+	mgr.entityChat.Register(handlers...)
+	return &mgr.entityChat
+}
+
+// EntityLeft registers an event handler that is invoked when an entity leaves the room.
+func (mgr *Manager) EntityLeft(handlers ...g.EventHandler[EntityArgs]) *g.Event[EntityArgs] {
+	mgr.entityLeft.Register(handlers...)
+	return &mgr.entityLeft
+}
+
+// Left registers an event handler that is invoked when the user leaves the room.
+func (mgr *Manager) Left(handlers ...g.EventHandler[Args]) *g.Event[Args] {
+	mgr.left.Register(handlers...)
+	return &mgr.left
+}
@@ -0,0 +1,857 @@
+package room
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	g "xabbo.b7c.io/goearth"
+	"xabbo.b7c.io/goearth/in"
+	"xabbo.b7c.io/goearth/internal/debug"
+)
+
+var dbg = debug.NewLogger("[room]")
+
+type Manager struct {
+	ix g.Interceptor
+
+	entered       g.Event[Args]
+	rightsUpdated g.VoidEvent
+	objectsLoaded g.Event[ObjectsArgs]
+	objectAdded   g.Event[ObjectArgs]
+	objectUpdated g.Event[ObjectUpdateArgs]
+	objectRemoved g.Event[ObjectArgs]
+	slide         g.Event[SlideArgs]
+	itemsLoaded   g.Event[ItemsArgs]
+	itemAdded     g.Event[ItemArgs]
+	itemUpdated   g.Event[ItemUpdateArgs]
+	itemRemoved   g.Event[ItemArgs]
+	entitiesAdded g.Event[EntitiesArgs]
+	entityUpdated g.Event[EntityUpdateArgs]
+	entityChat    g.Event[EntityChatArgs]
+	entityLeft    g.Event[EntityArgs]
+	left          g.Event[Args]
+
+	mtxCache  *sync.RWMutex
+	infoCache map[g.Id]Info
+
+	usersLoaded bool
+
+	mtxRoom   *sync.RWMutex
+	isInRoom  bool
+	roomId    g.Id
+	roomModel string
+	roomInfo  *Info
+	isOwner   bool // IsOwner indicates whether the user is the owner of the current room.
+	hasRights bool // HasRights indicates whether the user has rights in the current room.
+	heightmap []string
+
+	mtxObjs  *sync.RWMutex
+	objects  map[g.Id]Object
+	mtxItems *sync.RWMutex
+	items    map[g.Id]Item
+	mtxEnts  *sync.RWMutex
+	entities map[int]Entity
+}
+
+func NewManager(ix g.Interceptor) *Manager {
+	mgr := &Manager{
+		ix:        ix,
+		mtxRoom:   &sync.RWMutex{},
+		mtxCache:  &sync.RWMutex{},
+		infoCache: map[g.Id]Info{},
+		mtxObjs:   &sync.RWMutex{},
+		objects:   map[g.Id]Object{},
+		mtxItems:  &sync.RWMutex{},
+		items:     map[g.Id]Item{},
+		mtxEnts:   &sync.RWMutex{},
+		entities:  map[int]Entity{},
+	}
+	ix.Intercept(in.GetGuestRoomResult).With(mgr.handleGetGuestRoomResult)
+	ix.Intercept(in.OpenConnection).With(mgr.handleOpenConnection)
+	ix.Intercept(in.RoomReady).With(mgr.handleRoomReady)
+	ix.Intercept(in.YouAreController, in.YouAreNotController, in.YouAreOwner).With(mgr.handleRoomRights)
+	ix.Intercept(in.FloorHeightMap).With(mgr.handleFloorHeightMap)
+	ix.Intercept(in.Objects).With(mgr.handleObjects)
+	ix.Intercept(in.ObjectAdd).With(mgr.handleObjectAdd)
+	ix.Intercept(in.ObjectUpdate).With(mgr.handleObjectUpdate)
+	ix.Intercept(in.ObjectRemove).With(mgr.handleObjectRemove)
+	ix.Intercept(in.SlideObjectBundle).With(mgr.handleSlideObjectBundle)
+	ix.Intercept(in.Items).With(mgr.handleItems)
+	ix.Intercept(in.ItemAdd).With(mgr.handleItemAdd)
+	ix.Intercept(in.ItemUpdate).With(mgr.handleItemUpdate)
+	ix.Intercept(in.ItemRemove).With(mgr.handleItemRemove)
+	ix.Intercept(in.Users).With(mgr.handleUsers)
+	ix.Intercept(in.UserUpdate).With(mgr.handleUserUpdate)
+	ix.Intercept(in.UserChange).With(mgr.handleUserChange)
+	ix.Intercept(in.Chat, in.Whisper, in.Shout).With(mgr.handleChat)
+	ix.Intercept(in.UserRemove).With(mgr.handleUserRemove)
+	ix.Intercept(in.CloseConnection).With(mgr.handleCloseConnection)
+	return mgr
+}
+
+func (mgr *Manager) IsInRoom() bool {
+	return mgr.isInRoom
+}
+
+func (mgr *Manager) Id() g.Id {
+	return mgr.roomId
+}
+
+func (mgr *Manager) Model() string {
+	return mgr.roomModel
+}
+
+func (mgr *Manager) Info() *Info {
+	return mgr.roomInfo
+}
+
+func (mgr *Manager) IsOwner() bool {
+	return mgr.isOwner
+}
+
+func (mgr *Manager) HasRights() bool {
+	return mgr.hasRights
+}
+
+func (mgr *Manager) Heightmap() []string {
+	return mgr.heightmap
+}
+
+// Object gets a floor item in the room by its ID.
+// It returns nil if the object was not found.
+func (mgr *Manager) Object(id g.Id) *Object {
+	mgr.mtxObjs.RLock()
+	defer mgr.mtxObjs.RUnlock()
+
+	if obj, ok := mgr.objects[id]; ok {
+		return &obj
+	} else {
+		return nil
+	}
+}
+
+// Objects iterates over all floor items currently in the room.
+func (mgr *Manager) Objects(yield func(obj Object) bool) {
+	mgr.mtxObjs.RLock()
+	for _, obj := range mgr.objects {
+		mgr.mtxObjs.RUnlock()
+		if !yield(obj) {
+			return
+		}
+		mgr.mtxObjs.RLock()
+	}
+	mgr.mtxObjs.RUnlock()
+}
+
+// ObjectCount returns the number of objects in the room.
+func (mgr *Manager) ObjectCount() int {
+	return len(mgr.objects)
+}
+
+// Item gets a wall item in the room by its ID.
+// It returns nil if the item was not found.
+func (mgr *Manager) Item(id g.Id) *Item {
+	mgr.mtxItems.RLock()
+	defer mgr.mtxItems.RUnlock()
+
+	if item, ok := mgr.items[id]; ok {
+		return &item
+	} else {
+		return nil
+	}
+}
+
+// Items iterates over all wall items currently in the room.
+func (mgr *Manager) Items(yield func(item Item) bool) {
+	mgr.mtxItems.RLock()
+	for _, item := range mgr.items {
+		mgr.mtxItems.RUnlock()
+		if !yield(item) {
+			return
+		}
+		mgr.mtxItems.RLock()
+	}
+	mgr.mtxItems.RUnlock()
+}
+
+// ItemCount returns the number of items in the room.
+func (mgr *Manager) ItemCount() int {
+	return len(mgr.items)
+}
+
+// Entity gets an entity in the room by its index.
+// It returns nil if the entity was not found.
+func (mgr *Manager) Entity(index int) *Entity {
+	mgr.mtxEnts.RLock()
+	defer mgr.mtxEnts.RUnlock()
+
+	if ent, ok := mgr.entities[index]; ok {
+		return &ent
+	} else {
+		return nil
+	}
+}
+
+// EntityByName gets the entity with the specified name.
+// Names are case-insensitive.
+// Returns nil if it does not exist.
+func (mgr *Manager) EntityByName(name string) *Entity {
+	mgr.mtxEnts.RLock()
+	defer mgr.mtxEnts.RUnlock()
+
+	for _, ent := range mgr.entities {
+		if strings.EqualFold(ent.Name, name) {
+			return &ent
+		}
+	}
+	return nil
+}
+
+// Entities iterates over all entities currently in the room.
+func (mgr *Manager) Entities(yield func(ent Entity) bool) {
+	mgr.mtxEnts.RLock()
+	for _, ent := range mgr.entities {
+		mgr.mtxEnts.RUnlock()
+		if !yield(ent) {
+			return
+		}
+		mgr.mtxEnts.RLock()
+	}
+	mgr.mtxEnts.RUnlock()
+}
+
+// EntityCount returns the number of entities in the room.
+func (mgr *Manager) EntityCount() int {
+	return len(mgr.entities)
+}
+
+func (mgr *Manager) enterRoom(model string, id g.Id) (info Info, ok bool) {
+	mgr.mtxRoom.Lock()
+	defer mgr.mtxRoom.Unlock()
+
+	mgr.roomModel = model
+	mgr.roomId = id
+	mgr.isInRoom = true
+
+	mgr.mtxCache.RLock()
+	info, ok = mgr.infoCache[id]
+	mgr.mtxCache.RUnlock()
+	if ok {
+		mgr.roomInfo = &info
+	} else {
+		mgr.roomInfo = nil
+	}
+
+	return
+}
+
+func (mgr *Manager) leaveRoom() {
+	if mgr.isInRoom {
+		mgr.mtxRoom.Lock()
+		defer mgr.mtxRoom.Unlock()
+
+		id := mgr.roomId
+		info := mgr.roomInfo
+
+		mgr.usersLoaded = false
+
+		mgr.isInRoom = false
+		mgr.roomModel = ""
+		mgr.roomId = 0
+		mgr.roomInfo = nil
+		mgr.isOwner = false
+		mgr.hasRights = false
+		mgr.heightmap = []string{}
+		mgr.clearObjects()
+		mgr.clearItems()
+		mgr.clearEntities()
+
+		mgr.left.Dispatch(Args{Id: id, Info: info})
+
+		dbg.Printf("left room")
+	}
+}
+
+func (mgr *Manager) updateCache(info Info) {
+	mgr.mtxCache.Lock()
+	defer mgr.mtxCache.Unlock()
+	mgr.infoCache[info.Id] = info
+}
+
+func (mgr *Manager) addObjects(load bool, objs []Object) {
+	mgr.mtxObjs.Lock()
+	defer mgr.mtxObjs.Unlock()
+
+	for _, object := range objs {
+		mgr.objects[object.Id] = object
+	}
+
+	if load {
+		dbg.Printf("loaded %d objects", len(objs))
+	} else {
+		dbg.Printf("added object %s", objs[0])
+	}
+}
+
+func (mgr *Manager) updateObject(obj Object) (pre Object, ok bool) {
+	mgr.mtxObjs.Lock()
+	defer mgr.mtxObjs.Unlock()
+
+	if pre, ok = mgr.objects[obj.Id]; ok {
+		// ObjectUpdate does not include the owner name.
+		obj.OwnerName = pre.OwnerName
+		mgr.objects[obj.Id] = obj
+		dbg.Printf("updated object %s", obj)
+	} else {
+		dbg.Printf("WARNING: failed to find object to update (ID: %d)", obj.Id)
+	}
+
+	return
+}
+
+func (mgr *Manager) processSlideObjectBundle(bundle SlideObjectBundle) SlideArgs {
+	mgr.mtxObjs.Lock()
+	defer mgr.mtxObjs.Unlock()
+	mgr.mtxEnts.Lock()
+	defer mgr.mtxEnts.Unlock()
+
+	var pSource *Object
+	if bundle.RollerId != 0 {
+		if source, ok := mgr.objects[bundle.RollerId]; ok {
+			pSource = &source
+		} else {
+			dbg.Printf("failed to find source (ID: %d)", bundle.RollerId)
+		}
+	}
+
+	args := SlideArgs{
+		From:          bundle.From,
+		To:            bundle.To,
+		Source:        pSource,
+		SlideMoveType: bundle.SlideMoveType,
+	}
+
+	for _, bundleObj := range bundle.Objects {
+		obj, ok := mgr.objects[bundleObj.Id]
+		if ok {
+			obj.X = args.To.X
+			obj.Y = args.To.Y
+			obj.Z = bundleObj.ToZ
+			mgr.objects[obj.Id] = obj
+			args.ObjectSlides = append(args.ObjectSlides, SlideObjectArgs{
+				Object: obj,
+				From: Tile{
+					X: bundle.From.X,
+					Y: bundle.From.Y,
+					Z: bundleObj.FromZ,
+				},
+				To: Tile{
+					X: bundle.To.X,
+					Y: bundle.To.Y,
+					Z: bundleObj.ToZ,
+				},
+			})
+		} else {
+			dbg.Printf("failed to find object (ID: %d)", bundleObj.Id)
+		}
+	}
+
+	if bundle.SlideMoveType != SlideMoveTypeNone {
+		index := int(bundle.Entity.Id)
+		if ent, ok := mgr.entities[index]; ok {
+			ent.X = bundle.To.X
+			ent.Y = bundle.To.Y
+			ent.Z = bundle.Entity.ToZ
+			mgr.entities[ent.Index] = ent
+			args.EntitySlide = &SlideEntityArgs{
+				Entity: ent,
+				From: Tile{
+					X: bundle.From.X,
+					Y: bundle.From.Y,
+					Z: bundle.Entity.FromZ,
+				},
+				To: Tile{
+					X: bundle.To.X,
+					Y: bundle.To.Y,
+					Z: bundle.Entity.ToZ,
+				},
+			}
+		} else {
+			dbg.Printf("failed to find entity (index: %d)", index)
+		}
+	}
+
+	dbg.Printf("processed slide object bundle (%d objects, with entity: %t)", len(args.ObjectSlides), args.EntitySlide != nil)
+	return args
+}
+
+func (mgr *Manager) removeObject(id g.Id) (obj Object, ok bool) {
+	mgr.mtxObjs.Lock()
+	defer mgr.mtxObjs.Unlock()
+
+	if obj, ok = mgr.objects[id]; ok {
+		delete(mgr.objects, id)
+		dbg.Printf("removed object (ID: %d)", id)
+	} else {
+		dbg.Printf("WARNING: failed to remove object (ID: %d)", id)
+	}
+
+	return
+}
+
+func (mgr *Manager) clearObjects() {
+	mgr.mtxObjs.Lock()
+	defer mgr.mtxObjs.Unlock()
+	clear(mgr.objects)
+	mgr.objects = map[g.Id]Object{}
+}
+
+func (mgr *Manager) addItems(load bool, items []Item) {
+	mgr.mtxItems.Lock()
+	defer mgr.mtxItems.Unlock()
+
+	for _, item := range items {
+		if _, exists := mgr.items[item.Id]; exists {
+			dbg.Printf("WARNING: duplicate item (ID: %d)", item.Id)
+		}
+		mgr.items[item.Id] = item
+	}
+
+	if load {
+		dbg.Printf("loaded %d items", len(items))
+	} else {
+		dbg.Printf("added item %s", items[0])
+	}
+}
+
+func (mgr *Manager) updateItem(item Item) (pre Item, ok bool) {
+	mgr.mtxItems.Lock()
+	defer mgr.mtxItems.Unlock()
+
+	if pre, ok = mgr.items[item.Id]; ok {
+		// ItemUpdate does not include the owner name.
+		item.OwnerName = pre.OwnerName
+		mgr.items[item.Id] = item
+		dbg.Printf("updated item %s", item)
+	} else {
+		dbg.Printf("WARNING: failed to find item to update (ID: %d)", item.Id)
+	}
+
+	return
+}
+
+func (mgr *Manager) removeItem(id g.Id) (item Item, ok bool) {
+	mgr.mtxItems.Lock()
+	defer mgr.mtxItems.Unlock()
+
+	if item, ok = mgr.items[id]; ok {
+		delete(mgr.items, item.Id)
+		dbg.Printf("removed item %s", item)
+	} else {
+		dbg.Printf("WARNING: failed to find item to remove (ID: %d)", id)
+	}
+
+	return
+}
+
+func (mgr *Manager) clearItems() {
+	mgr.mtxItems.Lock()
+	defer mgr.mtxItems.Unlock()
+	clear(mgr.items)
+	mgr.items = map[g.Id]Item{}
+}
+
+func (mgr *Manager) addEntities(ents []Entity) {
+	mgr.mtxEnts.Lock()
+	defer mgr.mtxEnts.Unlock()
+
+	for _, entity := range ents {
+		if _, exists := mgr.entities[entity.Index]; exists {
+			dbg.Printf("WARNING: duplicate entity index: %d", entity.Index)
+		}
+		mgr.entities[entity.Index] = entity
+	}
+}
+
+func (mgr *Manager) updateEntities(statuses []EntityStatus) []EntityUpdateArgs {
+	mgr.mtxEnts.Lock()
+	defer mgr.mtxEnts.Unlock()
+
+	updates := make([]EntityUpdateArgs, 0, len(statuses))
+
+	for _, status := range statuses {
+		pre, ok := mgr.entities[status.Index]
+		if ok {
+			cur := pre
+			cur.Tile = status.Tile
+			cur.Dir = status.BodyDir
+			cur.HeadDir = status.HeadDir
+			cur.Action = status.Action
+			mgr.entities[status.Index] = cur
+			updates = append(updates, EntityUpdateArgs{Pre: pre, Entity: cur})
+		} else {
+			dbg.Printf("WARNING: failed to find entity to update (index: %d)", status.Index)
+		}
+	}
+
+	return updates
+}
+
+func (mgr *Manager) changeEntity(change EntityChange) (update EntityUpdateArgs, ok bool) {
+	mgr.mtxEnts.Lock()
+	defer mgr.mtxEnts.Unlock()
+
+	pre, ok := mgr.entities[change.Index]
+	if ok {
+		cur := pre
+		cur.Figure = change.Figure
+		cur.Gender = change.Gender
+		cur.Motto = change.Motto
+		cur.AchievementScore = change.AchievementScore
+		mgr.entities[change.Index] = cur
+		update = EntityUpdateArgs{Pre: pre, Entity: cur}
+	} else {
+		dbg.Printf("WARNING: failed to find entity to change (index: %d)", change.Index)
+	}
+
+	return
+}
+
+func (mgr *Manager) removeEntity(index int) (ent Entity, ok bool) {
+	mgr.mtxEnts.Lock()
+	defer mgr.mtxEnts.Unlock()
+
+	if ent, ok = mgr.entities[index]; ok {
+		delete(mgr.entities, index)
+		dbg.Printf("removed entity %q (index: %d)", ent.Name, ent.Index)
+	} else {
+		dbg.Printf("WARNING: failed to find entity to remove (index: %d)", index)
+	}
+
+	return
+}
+
+func (mgr *Manager) clearEntities() {
+	mgr.mtxEnts.Lock()
+	defer mgr.mtxEnts.Unlock()
+	clear(mgr.entities)
+	mgr.entities = map[int]Entity{}
+}
+
+// handlers
+
+func (mgr *Manager) handleGetGuestRoomResult(e *g.Intercept) {
+	var info Info
+	e.Packet.Skip(false) // enter room
+	e.Packet.Read(&info)
+
+	mgr.updateCache(info)
+
+	if mgr.isInRoom && mgr.roomId == info.Id {
+		mgr.mtxRoom.Lock()
+		mgr.roomInfo = &info
+		mgr.mtxRoom.Unlock()
+	}
+
+	dbg.Printf("cached room info (ID: %d)", info.Id)
+}
+
+func (mgr *Manager) handleOpenConnection(e *g.Intercept) {
+	mgr.leaveRoom()
+}
+
+func (mgr *Manager) handleRoomReady(e *g.Intercept) {
+	if mgr.isInRoom {
+		dbg.Printf("WARNING: already in room")
+	}
+
+	var model string
+	var roomId g.Id
+	e.Packet.Read(&model, &roomId)
+
+	mgr.enterRoom(model, roomId)
+
+	if mgr.roomInfo != nil {
+		mgr.entered.Dispatch(Args{Id: roomId, Info: mgr.roomInfo})
+		dbg.Printf("entered room %q by %s (ID: %d)", mgr.roomInfo.Name, mgr.roomInfo.Owner, mgr.roomInfo.Id)
+	} else {
+		mgr.entered.Dispatch(Args{Id: roomId})
+		dbg.Println("WARNING: failed to get room info from cache")
+		dbg.Printf("entered room (ID: %d)", roomId)
+	}
+}
+
+func (mgr *Manager) handleRoomRights(e *g.Intercept) {
+	if !mgr.isInRoom {
+		return
+	}
+
+	switch {
+	case e.Is(in.YouAreController):
+		mgr.hasRights = true
+		mgr.rightsUpdated.Dispatch()
+	case e.Is(in.YouAreNotController):
+		mgr.hasRights = false
+		mgr.rightsUpdated.Dispatch()
+	case e.Is(in.YouAreOwner):
+		mgr.isOwner = true
+	}
+}
+
+func (mgr *Manager) handleFloorHeightMap(e *g.Intercept) {
+	if !mgr.isInRoom {
+		return
+	}
+
+	e.Packet.Skip(false, 0) // scale, fixed wall height
+	mgr.heightmap = strings.Split(e.Packet.ReadString(), "\r")
+
+	if debug.Enabled {
+		if len(mgr.heightmap) > 0 {
+			dbg.Printf("received heightmap (%dx%d)", len(mgr.heightmap[0]), len(mgr.heightmap))
+		} else {
+			dbg.Println("WARNING: empty heightmap")
+		}
+	}
+}
+
+func (mgr *Manager) handleObjects(e *g.Intercept) {
+	if !mgr.isInRoom {
+		return
+	}
+
+	var objects Objects
+	e.Packet.Read(&objects)
+
+	mgr.addObjects(true, objects)
+
+	mgr.objectsLoaded.Dispatch(ObjectsArgs{Objects: objects})
+}
+
+func (mgr *Manager) handleObjectAdd(e *g.Intercept) {
+	if !mgr.isInRoom {
+		return
+	}
+
+	var object Object
+	e.Packet.Read(&object, &object.OwnerName)
+
+	mgr.addObjects(false, []Object{object})
+
+	mgr.objectAdded.Dispatch(ObjectArgs{Object: object})
+}
+
+func (mgr *Manager) handleObjectUpdate(e *g.Intercept) {
+	if !mgr.isInRoom {
+		return
+	}
+
+	var cur Object
+	e.Packet.Read(&cur)
+
+	if pre, ok := mgr.updateObject(cur); ok {
+		cur.OwnerName = pre.OwnerName
+		mgr.objectUpdated.Dispatch(ObjectUpdateArgs{Pre: pre, Object: cur})
+	}
+}
+
+func (mgr *Manager) handleObjectRemove(e *g.Intercept) {
+	if !mgr.isInRoom {
+		return
+	}
+
+	strId := e.Packet.ReadString()
+	id, err := strconv.ParseInt(strId, 10, 64)
+	if err != nil {
+		dbg.Printf("WARNING: invalid object id: %s", strId)
+		return
+	}
+
+	if obj, ok := mgr.removeObject(g.Id(id)); ok {
+		mgr.objectRemoved.Dispatch(ObjectArgs{Object: obj})
+	}
+}
+
+func (mgr *Manager) handleSlideObjectBundle(e *g.Intercept) {
+	if !mgr.isInRoom {
+		return
+	}
+
+	var bundle SlideObjectBundle
+	e.Packet.Read(&bundle)
+
+	args := mgr.processSlideObjectBundle(bundle)
+
+	mgr.slide.Dispatch(args)
+}
+
+func (mgr *Manager) handleItems(e *g.Intercept) {
+	if !mgr.isInRoom {
+		return
+	}
+
+	var items Items
+	e.Packet.Read(&items)
+
+	mgr.addItems(true, items)
+
+	mgr.itemsLoaded.Dispatch(ItemsArgs{Items: items})
+}
+
+func (mgr *Manager) handleItemAdd(e *g.Intercept) {
+	if !mgr.isInRoom {
+		return
+	}
+
+	var item Item
+	e.Packet.Read(&item, &item.OwnerName)
+
+	mgr.addItems(false, []Item{item})
+	mgr.itemAdded.Dispatch(ItemArgs{Item: item})
+}
+
+func (mgr *Manager) handleItemUpdate(e *g.Intercept) {
+	if !mgr.isInRoom {
+		return
+	}
+
+	var item Item
+	e.Packet.Read(&item)
+
+	if pre, ok := mgr.updateItem(item); ok {
+		item.OwnerName = pre.OwnerName
+		mgr.itemUpdated.Dispatch(ItemUpdateArgs{Pre: pre, Item: item})
+	}
+}
+
+func (mgr *Manager) handleItemRemove(e *g.Intercept) {
+	if !mgr.isInRoom {
+		return
+	}
+
+	strId := e.Packet.ReadString()
+	id, err := strconv.ParseInt(strId, 10, 64)
+	if err != nil {
+		dbg.Printf("WARNING: invalid item id: %s", strId)
+		return
+	}
+
+	if item, ok := mgr.removeItem(g.Id(id)); ok {
+		mgr.itemRemoved.Dispatch(ItemArgs{Item: item})
+	}
+}
+
+func (mgr *Manager) handleUsers(e *g.Intercept) {
+	if !mgr.isInRoom {
+		return
+	}
+
+	var ents []Entity
+	e.Packet.Read(&ents)
+
+	mgr.addEntities(ents)
+
+	// The first Users packet after entering a room loads the entities already in the room.
+	entered := mgr.usersLoaded
+	mgr.usersLoaded = true
+
+	mgr.entitiesAdded.Dispatch(EntitiesArgs{
+		Entered:  entered,
+		Entities: ents,
+	})
+
+	dbg.Printf("added %d entities", len(ents))
+}
+
+func (mgr *Manager) handleUserUpdate(e *g.Intercept) {
+	if !mgr.isInRoom {
+		return
+	}
+
+	var statuses []EntityStatus
+	e.Packet.Read(&statuses)
+
+	updates := mgr.updateEntities(statuses)
+
+	for _, update := range updates {
+		mgr.entityUpdated.Dispatch(update)
+		dbg.Printf("%s: %s", update.Entity.Name, update.Entity.Action)
+	}
+}
+
+func (mgr *Manager) handleUserChange(e *g.Intercept) {
+	if !mgr.isInRoom {
+		return
+	}
+
+	var change EntityChange
+	e.Packet.Read(&change)
+
+	if update, ok := mgr.changeEntity(change); ok {
+		mgr.entityUpdated.Dispatch(update)
+	}
+}
+
+func (mgr *Manager) handleChat(e *g.Intercept) {
+	if !mgr.isInRoom {
+		return
+	}
+
+	var chat Chat
+	e.Packet.Read(&chat)
+
+	var chatType ChatType
+	if e.Is(in.Chat) {
+		chatType = Talk
+	} else if e.Is(in.Whisper) {
+		chatType = Whisper
+	} else if e.Is(in.Shout) {
+		chatType = Shout
+	} else {
+		dbg.Printf("WARNING: unknown chat header: %q", e.Name())
+	}
+
+	if entity := mgr.Entity(chat.Index); entity != nil {
+		mgr.entityChat.Dispatch(EntityChatArgs{
+			EntityArgs: EntityArgs{Entity: *entity},
+			Type:       chatType,
+			Message:    chat.Message,
+			Gesture:    chat.Gesture,
+			Style:      chat.Style,
+		})
+		var indicator string
+		switch chatType {
+		case Talk:
+			indicator = "[-]"
+		case Shout:
+			indicator = "[!]"
+		case Whisper:
+			indicator = "[*]"
+		}
+		dbg.Printf("%s %s: %s", indicator, entity.Name, chat.Message)
+	} else {
+		dbg.Printf("WARNING: failed to find entity (index: %d)", chat.Index)
+	}
+}
+
+func (mgr *Manager) handleUserRemove(e *g.Intercept) {
+	if !mgr.isInRoom {
+		return
+	}
+
+	s := e.Packet.ReadString()
+	index, err := strconv.Atoi(s)
+	if err != nil {
+		dbg.Printf("WARNING: invalid index: %q", s)
+		return
+	}
+
+	if entity, ok := mgr.removeEntity(index); ok {
+		mgr.entityLeft.Dispatch(EntityArgs{Entity: entity})
+	}
+}
+
+func (mgr *Manager) handleCloseConnection(e *g.Intercept) {
+	mgr.leaveRoom()
+}
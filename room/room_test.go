@@ -0,0 +1,96 @@
+package room
+
+import (
+	"reflect"
+	"testing"
+
+	g "xabbo.b7c.io/goearth"
+)
+
+func TestStuffData(t *testing.T) {
+	tests := []StuffData{
+		{Format: LegacyFormat, Value: "1"},
+		{Format: MapFormat, Map: map[string]string{"state": "0", "rarity": "12"}},
+		{Format: StringArrayFormat, Strings: []string{"1", "2", "3"}},
+		{Format: VoteResultFormat, Value: "2", Result: 31},
+		{Format: EmptyFormat},
+		{Format: IntArrayFormat, Ints: []int{4, 5, 6}},
+		{Format: HighScoreFormat, Value: "0", ScoreType: 1, ClearType: 2,
+			HighScores: []HighScore{{Value: 100, Names: []string{"a", "b"}}}},
+		{Format: CrackableFormat, Value: "3", Hits: 7, Target: 10},
+		{Format: LegacyFormat, Flags: uniqueFlag, Value: "", UniqueSerial: 3, UniqueSeriesSize: 50},
+	}
+
+	for _, expected := range tests {
+		pkt := &g.Packet{Client: g.Flash, Header: g.Header{Dir: g.In}}
+		pkt.Write(expected)
+
+		var actual StuffData
+		pkt.ReadAt(0, &actual)
+
+		if !reflect.DeepEqual(expected, actual) {
+			t.Fatalf("incorrect stuff data, expected: %+v, actual: %+v", expected, actual)
+		}
+	}
+}
+
+func TestObjects(t *testing.T) {
+	pkt := &g.Packet{Client: g.Flash, Header: g.Header{Dir: g.In}}
+	// owners
+	pkt.Write(1, 100, "owner")
+	// objects
+	pkt.Write(2)
+	pkt.Write(1, 10, 3, 4, 2, "0.5", "1.0", 0, StuffData{Value: "1"}, -1, 1, 100)
+	pkt.Write(2, -5, 6, 7, 4, "1.5", "0.0", 0, StuffData{Format: EmptyFormat}, -1, 0, 100, "static_class")
+
+	var objects Objects
+	pkt.Pos = 0
+	pkt.Read(&objects)
+
+	if pkt.Pos != pkt.Length() {
+		t.Fatal("parser failed to read entire packet")
+	}
+
+	expected := Objects{
+		{Id: 1, Kind: 10, X: 3, Y: 4, Direction: 2, Z: 0.5, Height: 1.0,
+			Data: StuffData{Value: "1"}, SecondsToExpiration: -1, Usage: 1,
+			OwnerId: 100, OwnerName: "owner"},
+		{Id: 2, Kind: -5, X: 6, Y: 7, Direction: 4, Z: 1.5,
+			Data: StuffData{Format: EmptyFormat}, SecondsToExpiration: -1,
+			OwnerId: 100, OwnerName: "owner", StaticClass: "static_class"},
+	}
+	if !reflect.DeepEqual(expected, objects) {
+		t.Fatalf("incorrect objects, expected: %+v, actual: %+v", expected, objects)
+	}
+}
+
+func TestEntities(t *testing.T) {
+	pkt := &g.Packet{Client: g.Flash, Header: g.Header{Dir: g.In}}
+	pkt.Write(3)
+	// user
+	pkt.Write(1, "user", "motto", "hd-180-1", 0, 1, 2, "0.0", 2, User,
+		"M", 0, 0, "", "", 100, false)
+	// pet
+	pkt.Write(2, "pet", "", "0 0 ffffff", 1, 3, 4, "0.0", 4, Pet,
+		0, 1, "user", 0, false, false, false, false, false, false, 5, "std")
+	// private bot
+	pkt.Write(3, "bot", "", "hd-180-1", 2, 5, 6, "0.0", 0, PrivateBot,
+		"F", 1, "user", 2, int16(1), int16(2))
+
+	var ents []Entity
+	pkt.Pos = 0
+	pkt.Read(&ents)
+
+	if pkt.Pos != pkt.Length() {
+		t.Fatal("parser failed to read entire packet")
+	}
+	if len(ents) != 3 {
+		t.Fatalf("incorrect number of entities, expected: %d, actual: %d", 3, len(ents))
+	}
+	if ents[1].Level != 5 || ents[1].Posture != "std" {
+		t.Fatalf("incorrect pet data: %+v", ents[1])
+	}
+	if !reflect.DeepEqual(ents[2].Skills, []int16{1, 2}) {
+		t.Fatalf("incorrect bot skills: %v", ents[2].Skills)
+	}
+}
@@ -0,0 +1,161 @@
+package room
+
+import (
+	"fmt"
+	"strconv"
+
+	g "xabbo.b7c.io/goearth"
+)
+
+// StuffDataFormat represents the format of a furni's stuff data.
+type StuffDataFormat int
+
+const (
+	LegacyFormat StuffDataFormat = iota
+	MapFormat
+	StringArrayFormat
+	VoteResultFormat
+	EmptyFormat
+	IntArrayFormat
+	HighScoreFormat
+	CrackableFormat
+)
+
+// uniqueFlag indicates that the stuff data is followed by the unique serial number and series size.
+const uniqueFlag = 0x100
+
+// StuffData holds the state of a furni.
+// Only the fields relevant to the Format are populated.
+type StuffData struct {
+	Format StuffDataFormat
+	Flags  int
+	// Value holds the state of Legacy, VoteResult, HighScore and Crackable stuff data.
+	Value   string
+	Map     map[string]string
+	Strings []string
+	Ints    []int
+	// Result holds the result of VoteResult stuff data.
+	Result int
+	// ScoreType and ClearType hold the high score settings of HighScore stuff data.
+	ScoreType  int
+	ClearType  int
+	HighScores []HighScore
+	// Hits and Target hold the progress of Crackable stuff data.
+	Hits   int
+	Target int
+	// UniqueSerial and UniqueSeriesSize hold the limited edition number of unique furni.
+	UniqueSerial     int
+	UniqueSeriesSize int
+}
+
+// HighScore represents an entry on a high score furni.
+type HighScore struct {
+	Value int
+	Names []string
+}
+
+// IsUnique returns whether the stuff data is for a limited edition furni.
+func (data StuffData) IsUnique() bool {
+	return data.Flags&uniqueFlag != 0
+}
+
+// State returns the state of the furni represented by the stuff data.
+func (data StuffData) State() string {
+	switch data.Format {
+	case MapFormat:
+		return data.Map["state"]
+	case StringArrayFormat:
+		if len(data.Strings) > 0 {
+			return data.Strings[0]
+		}
+		return ""
+	case IntArrayFormat:
+		if len(data.Ints) > 0 {
+			return strconv.Itoa(data.Ints[0])
+		}
+		return ""
+	default:
+		return data.Value
+	}
+}
+
+func (data *StuffData) Parse(p *g.Packet, pos *int) {
+	flags := p.ReadIntPtr(pos)
+	*data = StuffData{
+		Format: StuffDataFormat(flags & 0xff),
+		Flags:  flags &^ 0xff,
+	}
+
+	switch data.Format {
+	case LegacyFormat:
+		p.ReadPtr(pos, &data.Value)
+	case MapFormat:
+		var n g.Length
+		p.ReadPtr(pos, &n)
+		data.Map = make(map[string]string, n)
+		for range n {
+			key := p.ReadStringPtr(pos)
+			data.Map[key] = p.ReadStringPtr(pos)
+		}
+	case StringArrayFormat:
+		p.ReadPtr(pos, &data.Strings)
+	case VoteResultFormat:
+		p.ReadPtr(pos, &data.Value, &data.Result)
+	case EmptyFormat:
+	case IntArrayFormat:
+		p.ReadPtr(pos, &data.Ints)
+	case HighScoreFormat:
+		p.ReadPtr(pos, &data.Value, &data.ScoreType, &data.ClearType, &data.HighScores)
+	case CrackableFormat:
+		p.ReadPtr(pos, &data.Value, &data.Hits, &data.Target)
+	default:
+		panic(fmt.Errorf("unknown stuff data format: %d", data.Format))
+	}
+
+	if data.IsUnique() {
+		p.ReadPtr(pos, &data.UniqueSerial, &data.UniqueSeriesSize)
+	}
+}
+
+func (data StuffData) Compose(p *g.Packet, pos *int) {
+	p.WriteIntPtr(pos, data.Flags|int(data.Format))
+
+	switch data.Format {
+	case LegacyFormat:
+		p.WritePtr(pos, data.Value)
+	case MapFormat:
+		p.WritePtr(pos, g.Length(len(data.Map)))
+		for key, value := range data.Map {
+			p.WritePtr(pos, key, value)
+		}
+	case StringArrayFormat:
+		p.WritePtr(pos, g.Length(len(data.Strings)))
+		for _, s := range data.Strings {
+			p.WritePtr(pos, s)
+		}
+	case VoteResultFormat:
+		p.WritePtr(pos, data.Value, data.Result)
+	case EmptyFormat:
+	case IntArrayFormat:
+		p.WritePtr(pos, g.Length(len(data.Ints)))
+		for _, n := range data.Ints {
+			p.WritePtr(pos, n)
+		}
+	case HighScoreFormat:
+		p.WritePtr(pos, data.Value, data.ScoreType, data.ClearType, g.Length(len(data.HighScores)))
+		for _, score := range data.HighScores {
+			p.WritePtr(pos, score.Value, g.Length(len(score.Names)))
+			for _, name := range score.Names {
+				p.WritePtr(pos, name)
+			}
+		}
+	case CrackableFormat:
+		p.WritePtr(pos, data.Value, data.Hits, data.Target)
+	default:
+		panic(fmt.Errorf("unknown stuff data format: %d", data.Format))
+	}
+
+	if data.IsUnique() {
+		p.WritePtr(pos, data.UniqueSerial, data.UniqueSeriesSize)
+	}
+}
@@ -1,6 +1,8 @@
 package catalog
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 
 	g "xabbo.b7c.io/goearth"
@@ -22,26 +24,140 @@ func (index *Index) Parse(p *g.Packet, pos *int) {
 	}
 }
 
+// Page represents a catalog page.
 type Page struct {
-	Id          string
-	Name        string
-	Layout      string
-	HeaderText  string
-	HeaderImage string
+	Id           string
+	Name         string
+	Layout       string
+	HeaderText   string
+	HeaderImage  string
+	TeaserText   string
+	TeaserImages []string
+	SpecialText  string
+	Offers       []Offer
 }
 
+// Parses a catalog page from a CATALOGPAGE packet.
+// The page is sent as a single string of lines in the format "key:value".
+func (page *Page) Parse(p *g.Packet, pos *int) {
+	*page = Page{}
+	for _, line := range strings.Split(p.ReadStringPtr(pos), "\r") {
+		if len(line) < 2 || line[1] != ':' {
+			continue
+		}
+		key, val := line[0], line[2:]
+		switch key {
+		case 'i':
+			page.Id = val
+		case 'n':
+			page.Name = val
+		case 'l':
+			page.Layout = val
+		case 'g':
+			page.HeaderImage = val
+		case 'h':
+			page.HeaderText = val
+		case 'w':
+			page.TeaserText = val
+		case 'e':
+			page.TeaserImages = strings.Split(val, ",")
+		case 's':
+			page.SpecialText = val
+		case 'p':
+			var offer Offer
+			if err := offer.parseFields(strings.Split(val, "\t")); err != nil {
+				dbg.Printf("WARNING: failed to parse offer %q: %s", val, err)
+				continue
+			}
+			offer.PageId = page.Id
+			page.Offers = append(page.Offers, offer)
+		}
+	}
+}
+
+// Product represents a furni included in an offer.
 type Product struct {
 	Type       string
 	ClassId    int
+	Class      string
 	Params     string
+	Dimensions string
+	Colors     string
 	Count      int
 	Expiration int
 }
 
+// Offer represents a purchasable offer on a catalog page.
 type Offer struct {
-	Code           int
+	// Code is the purchase code of the offer.
+	Code           string
 	Name           string
+	Description    string
 	PriceInCredits int
 	PriceInPixels  int
 	Products       []Product
+	// PageId is the ID of the page the offer was listed on.
+	PageId string
+}
+
+// Parses an offer from a string containing a single tab-separated product line,
+// excluding the "p:" prefix.
+func (offer *Offer) Parse(p *g.Packet, pos *int) {
+	s := p.ReadStringPtr(pos)
+	if err := offer.parseFields(strings.Split(s, "\t")); err != nil {
+		panic(err)
+	}
+}
+
+func (offer *Offer) parseFields(fields []string) (err error) {
+	if len(fields) < 10 {
+		return fmt.Errorf("invalid number of offer fields: %d", len(fields))
+	}
+	*offer = Offer{
+		Name:        fields[0],
+		Description: fields[1],
+		Code:        fields[8],
+		Products: []Product{{
+			Type:       fields[4],
+			Class:      fields[5],
+			Params:     fields[6],
+			Dimensions: fields[7],
+			Colors:     fields[9],
+			Count:      1,
+		}},
+	}
+	if offer.PriceInCredits, err = strconv.Atoi(fields[2]); err != nil {
+		return fmt.Errorf("invalid credit price: %q", fields[2])
+	}
+	if fields[3] != "" {
+		if offer.PriceInPixels, err = strconv.Atoi(fields[3]); err != nil {
+			return fmt.Errorf("invalid pixel price: %q", fields[3])
+		}
+	}
+	return nil
+}
+
+// PurchaseResult represents the result of a catalog purchase.
+type PurchaseResult int
+
+const (
+	PurchaseOk PurchaseResult = iota + 1
+	PurchaseNoBalance
+	PurchaseError
+	PurchaseNotAllowed
+)
+
+func (result PurchaseResult) String() string {
+	switch result {
+	case PurchaseOk:
+		return "ok"
+	case PurchaseNoBalance:
+		return "no balance"
+	case PurchaseError:
+		return "error"
+	case PurchaseNotAllowed:
+		return "not allowed"
+	default:
+		return strconv.Itoa(int(result))
+	}
 }
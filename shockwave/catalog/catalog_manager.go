@@ -0,0 +1,145 @@
+package catalog
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	g "xabbo.b7c.io/goearth"
+	"xabbo.b7c.io/goearth/internal/debug"
+	"xabbo.b7c.io/goearth/shockwave/in"
+	"xabbo.b7c.io/goearth/shockwave/out"
+)
+
+var dbg = debug.NewLogger("[catalog]")
+
+// ErrTimeout is returned when a catalog request does not receive a response in time.
+var ErrTimeout = errors.New("catalog request timed out")
+
+// Manager fetches and caches the catalog index and pages.
+type Manager struct {
+	ix g.Interceptor
+	// Language is the catalog language code used in requests.
+	Language string
+
+	mtx   *sync.RWMutex
+	index Index
+	pages map[string]Page
+}
+
+// NewManager creates a new catalog Manager using the provided extension.
+func NewManager(ix g.Interceptor) *Manager {
+	mgr := &Manager{
+		ix:       ix,
+		Language: "en",
+		mtx:      &sync.RWMutex{},
+		pages:    map[string]Page{},
+	}
+	ix.Intercept(in.CATALOGINDEX).With(mgr.handleCatalogIndex)
+	ix.Intercept(in.CATALOGPAGE).With(mgr.handleCatalogPage)
+	ix.Intercept(in.REFRESH_CATALOGUE).With(mgr.handleRefreshCatalogue)
+	return mgr
+}
+
+// Index returns the catalog index, requesting it if it has not been cached.
+func (mgr *Manager) Index() (Index, error) {
+	mgr.mtx.RLock()
+	index := mgr.index
+	mgr.mtx.RUnlock()
+	if index != nil {
+		return index, nil
+	}
+
+	mgr.ix.Send(out.GET_CATALOG_INDEX, []byte("production/"+mgr.Language))
+	if pkt := mgr.ix.Recv(in.CATALOGINDEX).TimeoutSec(10).Block().Wait(); pkt != nil {
+		pkt.Read(&index)
+		mgr.setIndex(index)
+		return index, nil
+	} else {
+		return nil, ErrTimeout
+	}
+}
+
+// Page returns the catalog page with the specified ID, requesting it if it has not been cached.
+func (mgr *Manager) Page(id string) (*Page, error) {
+	mgr.mtx.RLock()
+	page, ok := mgr.pages[id]
+	mgr.mtx.RUnlock()
+	if ok {
+		return &page, nil
+	}
+
+	mgr.ix.Send(out.GET_CATALOG_PAGE, []byte("production/"+id+"/"+mgr.Language))
+	if pkt := mgr.ix.Recv(in.CATALOGPAGE).TimeoutSec(10).Block().Wait(); pkt != nil {
+		pkt.Read(&page)
+		mgr.addPage(id, page)
+		return &page, nil
+	} else {
+		return nil, ErrTimeout
+	}
+}
+
+// Purchase purchases the specified offer with the provided extra data
+// and returns the result of the purchase.
+func (mgr *Manager) Purchase(offer Offer, extra string) (PurchaseResult, error) {
+	msg := strings.Join([]string{"production", offer.PageId, mgr.Language, offer.Code, extra, "0"}, "\r")
+	mgr.ix.Send(out.PURCHASE_FROM_CATALOG, []byte(msg))
+
+	pkt := mgr.ix.Recv(in.PURCHASE_OK, in.PURCHASE_NOBALANCE, in.PURCHASE_ERROR, in.PURCHASENOTALLOWED).
+		TimeoutSec(10).Wait()
+	if pkt == nil {
+		return 0, ErrTimeout
+	}
+
+	headers := mgr.ix.Headers()
+	switch {
+	case headers.Is(pkt.Header, in.PURCHASE_OK):
+		dbg.Printf("purchased %q", offer.Code)
+		return PurchaseOk, nil
+	case headers.Is(pkt.Header, in.PURCHASE_NOBALANCE):
+		return PurchaseNoBalance, nil
+	case headers.Is(pkt.Header, in.PURCHASENOTALLOWED):
+		return PurchaseNotAllowed, nil
+	default:
+		return PurchaseError, nil
+	}
+}
+
+func (mgr *Manager) setIndex(index Index) {
+	mgr.mtx.Lock()
+	defer mgr.mtx.Unlock()
+	mgr.index = index
+}
+
+func (mgr *Manager) addPage(id string, page Page) {
+	mgr.mtx.Lock()
+	defer mgr.mtx.Unlock()
+	mgr.pages[id] = page
+	if page.Id != "" && page.Id != id {
+		mgr.pages[page.Id] = page
+	}
+}
+
+// handlers
+
+func (mgr *Manager) handleCatalogIndex(e *g.Intercept) {
+	var index Index
+	e.Packet.Read(&index)
+	mgr.setIndex(index)
+	dbg.Printf("cached catalog index (%d pages)", len(index))
+}
+
+func (mgr *Manager) handleCatalogPage(e *g.Intercept) {
+	var page Page
+	e.Packet.Read(&page)
+	mgr.addPage(page.Id, page)
+	dbg.Printf("cached catalog page %q (%d offers)", page.Id, len(page.Offers))
+}
+
+func (mgr *Manager) handleRefreshCatalogue(e *g.Intercept) {
+	mgr.mtx.Lock()
+	defer mgr.mtx.Unlock()
+	mgr.index = nil
+	clear(mgr.pages)
+	dbg.Printf("catalog cache cleared")
+}
@@ -2,6 +2,7 @@ package catalog
 
 import (
 	"encoding/hex"
+	"reflect"
 	"testing"
 
 	g "xabbo.b7c.io/goearth"
@@ -44,5 +45,32 @@ func TestCatalogPage(t *testing.T) {
 	var page Page
 	pkt.Read(&page)
 
-	t.Log(page)
+	if page.Id != "set_mode" {
+		t.Fatalf("incorrect page ID, expected: %q, actual: %q", "set_mode", page.Id)
+	}
+	if page.Layout != "ctlg_layout2" {
+		t.Fatalf("incorrect page layout, expected: %q, actual: %q", "ctlg_layout2", page.Layout)
+	}
+	if len(page.Offers) != 16 {
+		t.Fatalf("incorrect number of offers, expected: %d, actual: %d", 16, len(page.Offers))
+	}
+
+	expected := Offer{
+		Code:           "A2 S2P",
+		Name:           "Double Bed",
+		Description:    "Give yourself space to stretch out",
+		PriceInCredits: 4,
+		Products: []Product{{
+			Type:       "s",
+			Class:      "bed_polyfon",
+			Params:     "0",
+			Dimensions: "2,3",
+			Colors:     "#ffffff,#ffffff,#ABD0D2,#ABD0D2",
+			Count:      1,
+		}},
+		PageId: "set_mode",
+	}
+	if !reflect.DeepEqual(expected, page.Offers[0]) {
+		t.Fatalf("incorrect offer, expected: %+v, actual: %+v", expected, page.Offers[0])
+	}
 }
@@ -1,6 +1,9 @@
 package console
 
-import "xabbo.b7c.io/goearth/shockwave/friend"
+import (
+	g "xabbo.b7c.io/goearth"
+	"xabbo.b7c.io/goearth/shockwave/friend"
+)
 
 type Init struct {
 	PersistentMsg    string
@@ -28,3 +31,42 @@ type CampaignMessage struct {
 	Link    string
 	Message string
 }
+
+// Category represents a friend list category.
+type Category struct {
+	Id   int
+	Name string
+}
+
+// UpdateType represents the type of a friend list update.
+type UpdateType int
+
+const (
+	Removed UpdateType = -1
+	Updated UpdateType = 0
+	Added   UpdateType = 1
+)
+
+// Update represents a single update to the friend list.
+type Update struct {
+	Type UpdateType
+	// Id is the ID of the removed friend, or the ID of the friend in Info if added or updated.
+	Id   int
+	Info friend.Info
+}
+
+func (update *Update) Parse(p *g.Packet, pos *int) {
+	*update = Update{Type: UpdateType(p.ReadIntPtr(pos))}
+	if update.Type == Removed {
+		p.ReadPtr(pos, &update.Id)
+	} else {
+		p.ReadPtr(pos, &update.Info)
+		update.Id = update.Info.Id
+	}
+}
+
+// ListUpdate represents a batch of updates to the friend list.
+type ListUpdate struct {
+	Categories []Category
+	Updates    []Update
+}
@@ -0,0 +1,82 @@
+package console
+
+import (
+	g "xabbo.b7c.io/goearth"
+	"xabbo.b7c.io/goearth/shockwave/friend"
+)
+
+// Args holds the arguments for friend events.
+type Args struct {
+	Friend friend.Info
+}
+
+// UpdateArgs holds the arguments for friend update events.
+type UpdateArgs struct {
+	Pre    friend.Info // Pre is the previous state of the friend before the update.
+	Friend friend.Info // Friend is the current state of the friend after the update.
+}
+
+// InitArgs holds the arguments for console initialization events.
+type InitArgs struct {
+	Init Init
+}
+
+// MessageArgs holds the arguments for console message events.
+type MessageArgs struct {
+	// Friend is the sender of the message, or nil if the sender is not in the friend list.
+	Friend  *friend.Info
+	Message Message
+}
+
+// RequestArgs holds the arguments for friend request events.
+type RequestArgs struct {
+	Request friend.Request
+}
+
+// Initialized registers an event handler that is invoked when the console is initialized.
+func (mgr *Manager) Initialized(handlers ...g.EventHandler[InitArgs]) *g.Event[InitArgs] {
+	mgr.initialized.Register(handlers...)
+	return &mgr.initialized
+}
+
+// Added registers an event handler that is invoked when a friend is added.
+func (mgr *Manager) Added(handlers ...g.EventHandler[Args]) *g.Event[Args] {
+	mgr.added.Register(handlers...)
+	return &mgr.added
+}
+
+// Updated registers an event handler that is invoked when a friend is updated.
+func (mgr *Manager) Updated(handlers ...g.EventHandler[UpdateArgs]) *g.Event[UpdateArgs] {
+	mgr.updated.Register(handlers...)
+	return &mgr.updated
+}
+
+// Removed registers an event handler that is invoked when a friend is removed.
+func (mgr *Manager) Removed(handlers ...g.EventHandler[Args]) *g.Event[Args] {
+	mgr.removed.Register(handlers...)
+	return &mgr.removed
+}
+
+// CameOnline registers an event handler that is invoked when a friend comes online.
+func (mgr *Manager) CameOnline(handlers ...g.EventHandler[Args]) *g.Event[Args] {
+	mgr.cameOnline.Register(handlers...)
+	return &mgr.cameOnline
+}
+
+// WentOffline registers an event handler that is invoked when a friend goes offline.
+func (mgr *Manager) WentOffline(handlers ...g.EventHandler[Args]) *g.Event[Args] {
+	mgr.wentOffline.Register(handlers...)
+	return &mgr.wentOffline
+}
+
+// MessageReceived registers an event handler that is invoked when a console message is received.
+func (mgr *Manager) MessageReceived(handlers ...g.EventHandler[MessageArgs]) *g.Event[MessageArgs] {
+	mgr.messageReceived.Register(handlers...)
+	return &mgr.messageReceived
+}
+
+// RequestReceived registers an event handler that is invoked when a friend request is received.
+func (mgr *Manager) RequestReceived(handlers ...g.EventHandler[RequestArgs]) *g.Event[RequestArgs] {
+	mgr.requestReceived.Register(handlers...)
+	return &mgr.requestReceived
+}
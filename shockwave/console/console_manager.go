@@ -0,0 +1,295 @@
+package console
+
+import (
+	"strings"
+	"sync"
+
+	g "xabbo.b7c.io/goearth"
+	"xabbo.b7c.io/goearth/internal/debug"
+	"xabbo.b7c.io/goearth/shockwave/friend"
+	"xabbo.b7c.io/goearth/shockwave/in"
+	"xabbo.b7c.io/goearth/shockwave/out"
+)
+
+var dbg = debug.NewLogger("[console]")
+
+// Manager tracks the state of the friend list and console.
+type Manager struct {
+	ix              g.Interceptor
+	initialized     g.Event[InitArgs]
+	added           g.Event[Args]
+	updated         g.Event[UpdateArgs]
+	removed         g.Event[Args]
+	cameOnline      g.Event[Args]
+	wentOffline     g.Event[Args]
+	messageReceived g.Event[MessageArgs]
+	requestReceived g.Event[RequestArgs]
+
+	mtx      *sync.RWMutex
+	init     Init
+	friends  map[int]friend.Info
+	requests map[int]friend.Request
+}
+
+// NewManager creates a new console Manager using the provided extension.
+func NewManager(ix g.Interceptor) *Manager {
+	mgr := &Manager{
+		ix:       ix,
+		mtx:      &sync.RWMutex{},
+		friends:  map[int]friend.Info{},
+		requests: map[int]friend.Request{},
+	}
+	ix.Intercept(in.FRIEND_LIST_INIT).With(mgr.handleFriendListInit)
+	ix.Intercept(in.FRIEND_LIST_UPDATE).With(mgr.handleFriendListUpdate)
+	ix.Intercept(in.FRIEND_REQUEST).With(mgr.handleFriendRequest)
+	ix.Intercept(in.ADD_BUDDY).With(mgr.handleAddBuddy)
+	ix.Intercept(in.REMOVE_BUDDY).With(mgr.handleRemoveBuddy)
+	ix.Intercept(in.MESSENGER_MESSAGE).With(mgr.handleMessengerMessage)
+	return mgr
+}
+
+// PersistentMessage returns the user's console motto.
+func (mgr *Manager) PersistentMessage() string {
+	mgr.mtx.RLock()
+	defer mgr.mtx.RUnlock()
+	return mgr.init.PersistentMsg
+}
+
+// Limit returns the maximum number of friends the user may have.
+func (mgr *Manager) Limit() int {
+	mgr.mtx.RLock()
+	defer mgr.mtx.RUnlock()
+	return mgr.init.UserLimit
+}
+
+// Friend gets the friend with the specified ID.
+func (mgr *Manager) Friend(id int) *friend.Info {
+	mgr.mtx.RLock()
+	defer mgr.mtx.RUnlock()
+	if info, ok := mgr.friends[id]; ok {
+		return &info
+	} else {
+		return nil
+	}
+}
+
+// FriendByName gets the friend with the specified name. The name is case-insensitive.
+func (mgr *Manager) FriendByName(name string) *friend.Info {
+	mgr.mtx.RLock()
+	defer mgr.mtx.RUnlock()
+	for _, info := range mgr.friends {
+		if strings.EqualFold(info.Name, name) {
+			return &info
+		}
+	}
+	return nil
+}
+
+// Friends iterates over all friends.
+func (mgr *Manager) Friends(yield func(info friend.Info) bool) {
+	mgr.mtx.RLock()
+	for _, info := range mgr.friends {
+		mgr.mtx.RUnlock()
+		if !yield(info) {
+			return
+		}
+		mgr.mtx.RLock()
+	}
+	mgr.mtx.RUnlock()
+}
+
+// FriendCount returns the number of friends.
+func (mgr *Manager) FriendCount() int {
+	mgr.mtx.RLock()
+	defer mgr.mtx.RUnlock()
+	return len(mgr.friends)
+}
+
+// Requests iterates over all pending friend requests.
+func (mgr *Manager) Requests(yield func(req friend.Request) bool) {
+	mgr.mtx.RLock()
+	for _, req := range mgr.requests {
+		mgr.mtx.RUnlock()
+		if !yield(req) {
+			return
+		}
+		mgr.mtx.RLock()
+	}
+	mgr.mtx.RUnlock()
+}
+
+// SendMessage sends a console message to the friend with the specified ID.
+func (mgr *Manager) SendMessage(id int, text string) {
+	mgr.ix.Send(out.MESSENGER_SENDMSG, 1, id, text)
+}
+
+// AcceptRequest accepts the friend requests from the users with the specified IDs.
+func (mgr *Manager) AcceptRequest(ids ...int) {
+	if len(ids) == 0 {
+		return
+	}
+	mgr.removeRequests(ids)
+	mgr.ix.Send(out.FRIENDLIST_ACCEPTFRIEND, idValues(ids)...)
+}
+
+// DeclineRequest declines the friend requests from the users with the specified IDs.
+func (mgr *Manager) DeclineRequest(ids ...int) {
+	if len(ids) == 0 {
+		return
+	}
+	mgr.removeRequests(ids)
+	mgr.ix.Send(out.FRIENDLIST_DECLINEFRIEND, append([]any{false}, idValues(ids)...)...)
+}
+
+// Follow follows the friend with the specified ID into their room.
+func (mgr *Manager) Follow(id int) {
+	mgr.ix.Send(out.FOLLOW_FRIEND, id)
+}
+
+// Remove removes the friends with the specified IDs.
+func (mgr *Manager) Remove(ids ...int) {
+	if len(ids) == 0 {
+		return
+	}
+	mgr.ix.Send(out.FRIENDLIST_REMOVEFRIEND, idValues(ids)...)
+}
+
+// idValues converts the list of IDs into count-prefixed packet values.
+func idValues(ids []int) []any {
+	values := make([]any, 0, 1+len(ids))
+	values = append(values, len(ids))
+	for _, id := range ids {
+		values = append(values, id)
+	}
+	return values
+}
+
+func (mgr *Manager) removeRequests(ids []int) {
+	mgr.mtx.Lock()
+	defer mgr.mtx.Unlock()
+	for _, id := range ids {
+		delete(mgr.requests, id)
+	}
+}
+
+func (mgr *Manager) addFriend(info friend.Info) {
+	mgr.mtx.Lock()
+	mgr.friends[info.Id] = info
+	mgr.mtx.Unlock()
+
+	dbg.Printf("added friend %q", info.Name)
+	mgr.added.Dispatch(Args{info})
+}
+
+func (mgr *Manager) updateFriend(info friend.Info) {
+	mgr.mtx.Lock()
+	pre, exists := mgr.friends[info.Id]
+	mgr.friends[info.Id] = info
+	mgr.mtx.Unlock()
+
+	if !exists {
+		dbg.Printf("WARNING: failed to find friend to update (ID: %d)", info.Id)
+		mgr.added.Dispatch(Args{info})
+		return
+	}
+
+	mgr.updated.Dispatch(UpdateArgs{pre, info})
+	if !pre.Online && info.Online {
+		dbg.Printf("%q came online", info.Name)
+		mgr.cameOnline.Dispatch(Args{info})
+	} else if pre.Online && !info.Online {
+		dbg.Printf("%q went offline", info.Name)
+		mgr.wentOffline.Dispatch(Args{info})
+	}
+}
+
+func (mgr *Manager) removeFriend(id int) {
+	mgr.mtx.Lock()
+	info, exists := mgr.friends[id]
+	delete(mgr.friends, id)
+	mgr.mtx.Unlock()
+
+	if !exists {
+		dbg.Printf("WARNING: failed to find friend to remove (ID: %d)", id)
+		return
+	}
+
+	dbg.Printf("removed friend %q", info.Name)
+	mgr.removed.Dispatch(Args{info})
+}
+
+// handlers
+
+func (mgr *Manager) handleFriendListInit(e *g.Intercept) {
+	var init Init
+	e.Packet.Read(&init)
+
+	mgr.mtx.Lock()
+	mgr.init = init
+	mgr.friends = make(map[int]friend.Info, len(init.Friends))
+	for _, info := range init.Friends {
+		mgr.friends[info.Id] = info
+	}
+	mgr.requests = make(map[int]friend.Request, len(init.Requests))
+	for _, req := range init.Requests {
+		mgr.requests[req.Id] = req
+	}
+	mgr.mtx.Unlock()
+
+	dbg.Printf("console initialized (%d friends, %d requests)", len(init.Friends), len(init.Requests))
+	mgr.initialized.Dispatch(InitArgs{init})
+}
+
+func (mgr *Manager) handleFriendListUpdate(e *g.Intercept) {
+	var update ListUpdate
+	e.Packet.Read(&update)
+
+	for _, u := range update.Updates {
+		switch u.Type {
+		case Added:
+			mgr.addFriend(u.Info)
+		case Updated:
+			mgr.updateFriend(u.Info)
+		case Removed:
+			mgr.removeFriend(u.Id)
+		default:
+			dbg.Printf("WARNING: unknown friend list update type: %d", u.Type)
+		}
+	}
+}
+
+func (mgr *Manager) handleFriendRequest(e *g.Intercept) {
+	var req friend.Request
+	e.Packet.Read(&req)
+
+	mgr.mtx.Lock()
+	mgr.requests[req.Id] = req
+	mgr.mtx.Unlock()
+
+	dbg.Printf("received friend request from %q", req.Name)
+	mgr.requestReceived.Dispatch(RequestArgs{req})
+}
+
+func (mgr *Manager) handleAddBuddy(e *g.Intercept) {
+	var info friend.Info
+	e.Packet.Read(&info)
+	mgr.addFriend(info)
+}
+
+func (mgr *Manager) handleRemoveBuddy(e *g.Intercept) {
+	var ids []int
+	e.Packet.Read(&ids)
+	for _, id := range ids {
+		mgr.removeFriend(id)
+	}
+}
+
+func (mgr *Manager) handleMessengerMessage(e *g.Intercept) {
+	var msg Message
+	e.Packet.Read(&msg)
+
+	mgr.messageReceived.Dispatch(MessageArgs{
+		Friend:  mgr.Friend(msg.SenderId),
+		Message: msg,
+	})
+}
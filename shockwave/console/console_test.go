@@ -0,0 +1,107 @@
+package console
+
+import (
+	"reflect"
+	"testing"
+
+	g "xabbo.b7c.io/goearth"
+	"xabbo.b7c.io/goearth/shockwave/friend"
+)
+
+var (
+	alice = friend.Info{
+		Id: 1001, Name: "alice", Gender: 1, Motto: "hello", Online: true, CanFollow: true,
+		LastAccess: "18-10-2026 17:03:13", Figure: "hd-180-1.ch-210-66",
+	}
+	bob = friend.Info{
+		Id: 1002, Name: "bob", LastAccess: "17-10-2026 09:12:45", Figure: "hd-600-1.ch-630-62", CategoryId: 2,
+	}
+)
+
+func TestListUpdate(t *testing.T) {
+	// The packets are synthetic, written in the FRIEND_LIST_UPDATE layout.
+	tests := []struct {
+		name     string
+		values   []any
+		expected ListUpdate
+	}{
+		{
+			name:   "added",
+			values: []any{g.Length(0), g.Length(1), 1, alice},
+			expected: ListUpdate{
+				Categories: []Category{},
+				Updates:    []Update{{Type: Added, Id: 1001, Info: alice}},
+			},
+		},
+		{
+			name:   "removed",
+			values: []any{g.Length(0), g.Length(1), -1, 1002},
+			expected: ListUpdate{
+				Categories: []Category{},
+				Updates:    []Update{{Type: Removed, Id: 1002}},
+			},
+		},
+		{
+			name: "mixed",
+			values: []any{
+				g.Length(2), 1, "Friends", 2, "Family",
+				g.Length(3), 0, alice, 1, bob, -1, 1003,
+			},
+			expected: ListUpdate{
+				Categories: []Category{{Id: 1, Name: "Friends"}, {Id: 2, Name: "Family"}},
+				Updates: []Update{
+					{Type: Updated, Id: 1001, Info: alice},
+					{Type: Added, Id: 1002, Info: bob},
+					{Type: Removed, Id: 1003},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := &g.Packet{Client: g.Shockwave, Header: g.Header{Dir: g.In}}
+			p.Write(test.values...)
+			p.Pos = 0
+
+			var update ListUpdate
+			p.Read(&update)
+
+			if p.Pos < len(p.Data) {
+				t.Fatalf("parser failed to read entire packet")
+			}
+			if !reflect.DeepEqual(update, test.expected) {
+				t.Fatalf("incorrect update, expected: %+v, actual: %+v", test.expected, update)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []any
+		expected Update
+	}{
+		{"removed", []any{-1, 1002}, Update{Type: Removed, Id: 1002}},
+		{"updated", []any{0, alice}, Update{Type: Updated, Id: 1001, Info: alice}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := &g.Packet{Client: g.Shockwave, Header: g.Header{Dir: g.In}}
+			p.Write(test.values...)
+			p.Pos = 0
+
+			var update Update
+			p.Read(&update)
+
+			if p.Pos < len(p.Data) {
+				t.Fatalf("parser failed to read entire packet")
+			}
+			if update != test.expected {
+				t.Fatalf("incorrect update, expected: %+v, actual: %+v", test.expected, update)
+			}
+		})
+	}
+}
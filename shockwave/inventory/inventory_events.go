@@ -7,12 +7,45 @@ type ItemArgs struct {
 	Item Item
 }
 
+// ScanProgressArgs holds the arguments for inventory scan progress events.
+type ScanProgressArgs struct {
+	// Page is the number of the page that was scanned, starting from 1.
+	Page int
+	// Items is the number of items on the page.
+	Items int
+	// Total is the number of unique items scanned so far.
+	Total int
+	// Changed indicates whether the inventory has changed since the scan began.
+	Changed bool
+}
+
 // Updated registers an event handler that is invoked when the inventory is updated.
-func (mgr *Manager) Updated(handler g.VoidHandler) {
-	mgr.updated.Register(handler)
+func (mgr *Manager) Updated(handlers ...g.VoidHandler) *g.VoidEvent {
+	mgr.updated.Register(handlers...)
+	return &mgr.updated
+}
+
+// Invalidated registers an event handler that is invoked when the inventory becomes stale
+// and needs to be rescanned. See Manager.Stale.
+func (mgr *Manager) Invalidated(handlers ...g.VoidHandler) *g.VoidEvent {
+	mgr.invalidated.Register(handlers...)
+	return &mgr.invalidated
+}
+
+// ItemAdded registers an event handler that is invoked when an item is added to the inventory.
+func (mgr *Manager) ItemAdded(handlers ...g.EventHandler[ItemArgs]) *g.Event[ItemArgs] {
+	mgr.itemAdded.Register(handlers...)
+	return &mgr.itemAdded
 }
 
 // ItemRemoved registers an event handler that is invoked when an item is removed from the inventory.
-func (mgr *Manager) ItemRemoved(handler g.EventHandler[ItemArgs]) {
-	mgr.itemRemoved.Register(handler)
+func (mgr *Manager) ItemRemoved(handlers ...g.EventHandler[ItemArgs]) *g.Event[ItemArgs] {
+	mgr.itemRemoved.Register(handlers...)
+	return &mgr.itemRemoved
+}
+
+// ScanProgress registers an event handler that is invoked after each page of an inventory scan.
+func (mgr *Manager) ScanProgress(handlers ...g.EventHandler[ScanProgressArgs]) *g.Event[ScanProgressArgs] {
+	mgr.scanProgress.Register(handlers...)
+	return &mgr.scanProgress
 }
@@ -3,8 +3,9 @@ package inventory
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 	"sync"
-	"time"
 
 	g "xabbo.b7c.io/goearth"
 	"xabbo.b7c.io/goearth/internal/debug"
@@ -18,30 +19,52 @@ var ErrScanSuccess = fmt.Errorf("scan completed successfully")
 
 // Manager tracks the state of the inventory.
 type Manager struct {
-	ix          g.Interceptor
-	updated     g.VoidEvent
-	itemRemoved g.Event[ItemArgs]
-
-	scanCtx   context.Context
-	scanDone  func(error)
-	scanPage  int
-	scanItems map[int]struct{}
-	scanCh    chan []Item
+	ix           g.Interceptor
+	updated      g.VoidEvent
+	invalidated  g.VoidEvent
+	itemAdded    g.Event[ItemArgs]
+	itemRemoved  g.Event[ItemArgs]
+	scanProgress g.Event[ScanProgressArgs]
+
+	scanCtx      context.Context
+	scanDone     context.CancelCauseFunc
+	scanPage     int
+	scanItems    map[int]struct{}
+	scanCh       chan []Item
+	scanChanged  bool // scanChanged indicates whether the inventory changed during the scan.
+	scanComplete bool // scanComplete indicates whether the last scan finished.
+	stripMoved   bool // stripMoved indicates whether the client requested a page since the last scan stopped.
 
 	mtx   *sync.RWMutex
 	items map[int]Item
+	stale bool
+	// placing maps the furni IDs of items being placed in a room to their inventory item IDs.
+	placing map[int]int
+	// picking holds the furni IDs of items being picked up from a room.
+	picking map[int]struct{}
 }
 
 // NewManager creates a new inventory Manager using the provided extension.
 func NewManager(ix g.Interceptor) *Manager {
 	mgr := &Manager{
-		ix:    ix,
-		mtx:   &sync.RWMutex{},
-		items: map[int]Item{},
+		ix:      ix,
+		mtx:     &sync.RWMutex{},
+		items:   map[int]Item{},
+		placing: map[int]int{},
+		picking: map[int]struct{}{},
 	}
 	ix.Intercept(out.GETSTRIP).With(mgr.handleGetStrip)
 	ix.Intercept(in.STRIPINFO_2).With(mgr.handleStripInfo2)
 	ix.Intercept(in.REMOVESTRIPITEM).With(mgr.handleRemoveStripItem)
+	ix.Intercept(in.STRIPUPDATED).With(mgr.handleStripUpdated)
+	ix.Intercept(out.PLACESTUFF).With(mgr.handlePlaceStuff)
+	ix.Intercept(in.ACTIVEOBJECT_ADD).With(mgr.handleActiveObjectAdd)
+	ix.Intercept(in.ITEMS_2).With(mgr.handleItemAdd)
+	ix.Intercept(out.ADDSTRIPITEM, out.REMOVESTUFF).With(mgr.handlePickUp)
+	ix.Intercept(in.ACTIVEOBJECT_REMOVE, in.REMOVEITEM).With(mgr.handleFurniRemove)
+	ix.Intercept(in.OPC_OK, in.CLC).With(mgr.handleRoomChange)
+	ix.Intercept(in.TRADE_COMPLETED, in.TRADE_COMPLETED_2).With(mgr.handleTradeCompleted)
+	ix.Intercept(in.PURCHASE_OK).With(mgr.handlePurchaseOk)
 	return mgr
 }
 
@@ -71,108 +94,45 @@ func (mgr *Manager) Items(yield func(item Item) bool) {
 
 // ItemCount returns the number of items in the inventory.
 func (mgr *Manager) ItemCount() int {
+	mgr.mtx.RLock()
+	defer mgr.mtx.RUnlock()
 	return len(mgr.items)
 }
 
-// Scan performs a full load of the inventory by requesting each inventory page.
-// The returned context is canceled once the scan has finished successfully or unsuccessfully.
-// Calling context.Cause on the scan context will return ErrScanSuccess if the scan completed successfully.
-// Otherwise it will return context.DeadlineExceeded if the operation timed out,
-// or context.Canceled if it was explicitly canceled.
-// Multiple calls to Scan while a scan is in progress will return the same context.
-func (mgr *Manager) Scan() context.Context {
-	mgr.mtx.Lock()
-	defer mgr.mtx.Unlock()
-
-	if mgr.scanCtx != nil {
-		return mgr.scanCtx
-	}
-
-	dbg.Printf("beginning scan")
-
-	mgr.scanPage = 0
-	mgr.scanItems = map[int]struct{}{}
-	mgr.scanCtx, mgr.scanDone = context.WithCancelCause(mgr.ix.Context())
-	mgr.scanCh = make(chan []Item)
-
-	go mgr.performScan()
-
-	return mgr.scanCtx
+// Stale returns whether the inventory has changed in a way that could not be tracked,
+// e.g. after a purchase, trade or picking up furni, and a rescan is needed to bring it up to date.
+// The flag is cleared once a scan completes successfully.
+func (mgr *Manager) Stale() bool {
+	mgr.mtx.RLock()
+	defer mgr.mtx.RUnlock()
+	return mgr.stale
 }
 
-func (mgr *Manager) performScan() {
-	defer func() {
-		mgr.mtx.Lock()
-		defer mgr.mtx.Unlock()
-		mgr.scanDone(context.DeadlineExceeded)
-		mgr.scanCtx = nil
-	}()
-
-	attempt := 1
-	mgr.ix.Send(out.GETSTRIP, []byte("new"))
-scan:
-	for {
-		select {
-		case items := <-mgr.scanCh:
-			mgr.scanPage++
-			var last, wrapped bool
-			if len(items) < 9 {
-				last = true
-			} else {
-				for _, item := range items {
-					if _, wrapped = mgr.scanItems[item.ItemId]; wrapped {
-						break
-					}
-					mgr.scanItems[item.ItemId] = struct{}{}
-				}
-			}
-			if !wrapped {
-				dbg.Printf("scanned page %d (%d items)", mgr.scanPage, len(items))
-			}
-			if last || wrapped {
-				dbg.Printf("completing scan")
-				mgr.scanDone(ErrScanSuccess)
-			} else {
-				// continue scan
-				select {
-				case <-time.After(550 * time.Millisecond):
-					dbg.Printf("continuing scan")
-					mgr.ix.Send(out.GETSTRIP, []byte("next"))
-				case <-mgr.scanCtx.Done():
-					break scan
-				}
-			}
-		case <-time.After(time.Second):
-			// timed out
-			if attempt < 3 {
-				attempt++
-				// retry scan
-				dbg.Printf("timed out, retrying (attempt %d)", attempt)
-				mgr.ix.Send(out.GETSTRIP, []byte("next"))
-			} else {
-				dbg.Printf("timed out, aborting (attempt %d)", attempt)
-				break scan
-			}
-		case <-mgr.scanCtx.Done():
-			// canceled
-			break scan
-		}
+// invalidate marks the inventory as stale and dispatches the invalidated event.
+func (mgr *Manager) invalidate(reason string) {
+	mgr.mtx.Lock()
+	wasStale := mgr.stale
+	mgr.stale = true
+	mgr.mtx.Unlock()
+
+	dbg.Printf("inventory invalidated (%s)", reason)
+	mgr.markScanChanged()
+	if !wasStale {
+		mgr.invalidated.Dispatch()
 	}
 }
 
-func (mgr *Manager) CancelScan() bool {
-	mgr.mtx.Lock()
-	defer mgr.mtx.Unlock()
+// refresh invalidates the inventory and requests the current inventory page,
+// so that any items added to it are loaded. Nothing is requested while a scan is in progress.
+func (mgr *Manager) refresh(reason string) {
+	mgr.invalidate(reason)
 
-	if mgr.scanCtx == nil {
-		return false
+	mgr.mtx.RLock()
+	scanning := mgr.scanCtx != nil
+	mgr.mtx.RUnlock()
+	if !scanning {
+		mgr.ix.Send(out.GETSTRIP, []byte("update"))
 	}
-
-	dbg.Printf("cancelling scan")
-
-	mgr.scanDone(context.Canceled)
-	mgr.scanCtx = nil
-	return true
 }
 
 func (mgr *Manager) loadItems(items []Item) (added []Item) {
@@ -211,11 +171,13 @@ func (mgr *Manager) removeItem(id int) (item Item, ok bool) {
 // handlers
 
 func (mgr *Manager) handleGetStrip(e *g.Intercept) {
-	mgr.mtx.RLock()
-	defer mgr.mtx.RUnlock()
+	mgr.mtx.Lock()
+	defer mgr.mtx.Unlock()
 	if mgr.scanCtx != nil {
 		dbg.Printf("blocking getstrip (scan in progress)")
 		e.Block()
+	} else {
+		mgr.stripMoved = true
 	}
 }
 
@@ -223,7 +185,10 @@ func (mgr *Manager) handleStripInfo2(e *g.Intercept) {
 	var inv Inventory
 	e.Packet.Read(&inv)
 
-	mgr.loadItems(inv.Items)
+	added := mgr.loadItems(inv.Items)
+	for _, item := range added {
+		mgr.itemAdded.Dispatch(ItemArgs{item})
+	}
 	mgr.updated.Dispatch()
 
 	mgr.mtx.Lock()
@@ -241,7 +206,119 @@ func (mgr *Manager) handleStripInfo2(e *g.Intercept) {
 
 func (mgr *Manager) handleRemoveStripItem(e *g.Intercept) {
 	itemId := e.Packet.ReadInt()
+	mgr.markScanChanged()
 	if item, ok := mgr.removeItem(itemId); ok {
 		mgr.itemRemoved.Dispatch(ItemArgs{item})
 	}
 }
+
+func (mgr *Manager) handleStripUpdated(e *g.Intercept) {
+	mgr.refresh("strip updated")
+}
+
+func (mgr *Manager) handlePlaceStuff(e *g.Intercept) {
+	// The item ID is the first field of the raw payload,
+	// followed by the coordinates or wall location.
+	fields := strings.Fields(string(e.Packet.Data))
+	if len(fields) == 0 {
+		return
+	}
+	itemId, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return
+	}
+
+	mgr.mtx.Lock()
+	defer mgr.mtx.Unlock()
+	if item, ok := mgr.items[itemId]; ok {
+		mgr.placing[item.Id] = itemId
+	}
+}
+
+func (mgr *Manager) handleActiveObjectAdd(e *g.Intercept) {
+	// The object ID is read as a string.
+	if id, err := strconv.Atoi(e.Packet.ReadString()); err == nil {
+		mgr.placed(id)
+	}
+}
+
+func (mgr *Manager) handleItemAdd(e *g.Intercept) {
+	// The wall item ID is the first tab-separated field.
+	strId, _, _ := strings.Cut(e.Packet.ReadString(), "\t")
+	if id, err := strconv.Atoi(strId); err == nil {
+		mgr.placed(id)
+	}
+}
+
+// placed removes the inventory item that was placed in the room as the furni with the specified ID.
+func (mgr *Manager) placed(id int) {
+	mgr.mtx.Lock()
+	itemId, ok := mgr.placing[id]
+	delete(mgr.placing, id)
+	mgr.mtx.Unlock()
+
+	if ok {
+		if item, ok := mgr.removeItem(itemId); ok {
+			mgr.itemRemoved.Dispatch(ItemArgs{item})
+		}
+	}
+}
+
+func (mgr *Manager) handlePickUp(e *g.Intercept) {
+	// The furni ID is the last field of the raw payload,
+	// e.g. "new stuff <id>" for ADDSTRIPITEM, or "<id>" for REMOVESTUFF.
+	fields := strings.Fields(string(e.Packet.Data))
+	if len(fields) == 0 {
+		return
+	}
+	id, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil {
+		return
+	}
+
+	mgr.mtx.Lock()
+	defer mgr.mtx.Unlock()
+	mgr.picking[id] = struct{}{}
+}
+
+func (mgr *Manager) handleFurniRemove(e *g.Intercept) {
+	// The furni ID is read as a string.
+	id, err := strconv.Atoi(e.Packet.ReadString())
+	if err != nil {
+		return
+	}
+
+	mgr.mtx.Lock()
+	_, ok := mgr.picking[id]
+	delete(mgr.picking, id)
+	mgr.mtx.Unlock()
+
+	if ok {
+		mgr.refresh("picked up furni")
+	}
+}
+
+func (mgr *Manager) handleRoomChange(e *g.Intercept) {
+	mgr.mtx.Lock()
+	defer mgr.mtx.Unlock()
+	clear(mgr.placing)
+	clear(mgr.picking)
+}
+
+func (mgr *Manager) handleTradeCompleted(e *g.Intercept) {
+	mgr.refresh("trade completed")
+}
+
+func (mgr *Manager) handlePurchaseOk(e *g.Intercept) {
+	mgr.refresh("purchase")
+}
+
+// markScanChanged records that the inventory changed while a scan is in progress.
+func (mgr *Manager) markScanChanged() {
+	mgr.mtx.Lock()
+	defer mgr.mtx.Unlock()
+	if mgr.scanCtx != nil && !mgr.scanChanged {
+		dbg.Printf("inventory changed during scan")
+		mgr.scanChanged = true
+	}
+}
@@ -0,0 +1,310 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"xabbo.b7c.io/goearth/shockwave/out"
+)
+
+// PageSize is the number of items on a full inventory page.
+const PageSize = 9
+
+// Default scan options.
+const (
+	DefaultScanDelay   = 550 * time.Millisecond
+	DefaultScanTimeout = time.Second
+	DefaultScanRetries = 2
+)
+
+var (
+	// ErrScanChanged is the cause of a scan context when the scan completed,
+	// but the inventory changed during the scan, so some items may have been missed.
+	ErrScanChanged = errors.New("inventory changed during scan")
+	// ErrScanLimit is the cause of a scan context when the scan stopped after reaching the maximum number of pages.
+	// The scan may be resumed with ResumeScan.
+	ErrScanLimit = errors.New("scan page limit reached")
+
+	// errScanStale is returned when processing a page for a scan that is no longer in progress.
+	errScanStale = errors.New("scan is no longer in progress")
+)
+
+// ScanOptions holds the options used when scanning the inventory.
+type ScanOptions struct {
+	// Delay is the delay between requesting each page.
+	Delay time.Duration
+	// Timeout is the time to wait for a page before retrying.
+	Timeout time.Duration
+	// Retries is the number of times a page is requested again after timing out.
+	Retries int
+	// MaxPages is the maximum number of pages to scan, or 0 for no limit.
+	MaxPages int
+	// Progress is called after each page is scanned. Optional.
+	Progress func(ScanProgressArgs)
+}
+
+// DefaultScanOptions returns the default scan options.
+func DefaultScanOptions() ScanOptions {
+	return ScanOptions{
+		Delay:   DefaultScanDelay,
+		Timeout: DefaultScanTimeout,
+		Retries: DefaultScanRetries,
+	}
+}
+
+// Scan performs a full load of the inventory by requesting each inventory page with the default scan options.
+// The returned context is canceled once the scan has finished successfully or unsuccessfully.
+// Calling context.Cause on the scan context will return ErrScanSuccess if the scan completed successfully,
+// or ErrScanChanged if it completed but the inventory changed during the scan.
+// Otherwise it will return context.DeadlineExceeded if the operation timed out,
+// context.Canceled if it was explicitly canceled, or ErrScanLimit if the page limit was reached.
+// Multiple calls to Scan while a scan is in progress will return the same context.
+func (mgr *Manager) Scan() context.Context {
+	return mgr.ScanWith(DefaultScanOptions())
+}
+
+// ScanWith performs a full load of the inventory with the specified options. See Scan.
+func (mgr *Manager) ScanWith(opts ScanOptions) context.Context {
+	return mgr.startScan(opts, false)
+}
+
+// ResumeScan resumes the last scan that was canceled, timed out or reached its page limit,
+// continuing from the last page that was scanned.
+// If there is no scan to resume, a new scan is started. See Scan.
+func (mgr *Manager) ResumeScan(opts ScanOptions) context.Context {
+	return mgr.startScan(opts, true)
+}
+
+// CanResumeScan returns whether there is an incomplete scan that can be resumed.
+func (mgr *Manager) CanResumeScan() bool {
+	mgr.mtx.RLock()
+	defer mgr.mtx.RUnlock()
+	return mgr.canResumeScan()
+}
+
+// canResumeScan returns whether there is an incomplete scan that can be resumed.
+// The lock must be held.
+func (mgr *Manager) canResumeScan() bool {
+	return mgr.scanCtx == nil && !mgr.scanComplete && mgr.scanPage > 0
+}
+
+func (mgr *Manager) startScan(opts ScanOptions, resume bool) context.Context {
+	mgr.mtx.Lock()
+	defer mgr.mtx.Unlock()
+
+	if mgr.scanCtx != nil {
+		return mgr.scanCtx
+	}
+
+	if opts.Delay <= 0 {
+		opts.Delay = DefaultScanDelay
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultScanTimeout
+	}
+	if opts.Retries < 0 {
+		opts.Retries = 0
+	}
+
+	start, skip := "new", 0
+	if resume && mgr.canResumeScan() {
+		if mgr.stripMoved {
+			// The client has requested another page since the scan stopped,
+			// so skip over the pages that were already scanned.
+			skip = mgr.scanPage
+		} else {
+			start = "next"
+		}
+		dbg.Printf("resuming scan from page %d", mgr.scanPage)
+	} else {
+		dbg.Printf("beginning scan")
+		mgr.scanPage = 0
+		mgr.scanItems = map[int]struct{}{}
+		mgr.scanChanged = false
+	}
+
+	mgr.scanComplete = false
+	mgr.stripMoved = false
+	ctx, done := context.WithCancelCause(mgr.ix.Context())
+	mgr.scanCtx, mgr.scanDone = ctx, done
+	mgr.scanCh = make(chan []Item)
+
+	go mgr.performScan(ctx, done, mgr.scanCh, opts, start, skip)
+
+	return ctx
+}
+
+func (mgr *Manager) performScan(ctx context.Context, done context.CancelCauseFunc, pages <-chan []Item,
+	opts ScanOptions, cmd string, skip int) {
+	defer func() {
+		mgr.mtx.Lock()
+		defer mgr.mtx.Unlock()
+		done(context.DeadlineExceeded)
+		if mgr.scanCtx == ctx {
+			mgr.scanCtx = nil
+		}
+	}()
+
+	attempt := 0
+	mgr.ix.Send(out.GETSTRIP, []byte(cmd))
+	for {
+		select {
+		case items := <-pages:
+			attempt = 0
+			last := len(items) < PageSize
+			if skip > 0 && !last {
+				skip--
+				dbg.Printf("skipped page (%d remaining)", skip)
+			} else {
+				skip = 0
+				result := mgr.processScanPage(ctx, items, opts)
+				if result == errScanStale {
+					dbg.Printf("dropped page for canceled scan")
+					return
+				} else if result != nil {
+					mgr.completeScan(ctx, done, result)
+					return
+				}
+			}
+			// continue scan
+			select {
+			case <-time.After(opts.Delay):
+				dbg.Printf("continuing scan")
+				cmd = "next"
+				mgr.ix.Send(out.GETSTRIP, []byte(cmd))
+			case <-ctx.Done():
+				return
+			}
+		case <-time.After(opts.Timeout):
+			if attempt < opts.Retries {
+				attempt++
+				dbg.Printf("timed out, retrying (retry %d)", attempt)
+				mgr.ix.Send(out.GETSTRIP, []byte(cmd))
+			} else {
+				dbg.Printf("timed out, aborting (retry %d)", attempt)
+				return
+			}
+		case <-ctx.Done():
+			// canceled
+			return
+		}
+	}
+}
+
+// processScanPage records a scanned page and reports progress.
+// Returns the cause to complete the scan with, nil if the scan should continue,
+// or errScanStale if ctx is no longer the current scan.
+func (mgr *Manager) processScanPage(ctx context.Context, items []Item, opts ScanOptions) error {
+	mgr.mtx.Lock()
+	if mgr.scanCtx != ctx {
+		mgr.mtx.Unlock()
+		return errScanStale
+	}
+
+	last := len(items) < PageSize
+	wrapped := false
+	if !last {
+		for _, item := range items {
+			if _, wrapped = mgr.scanItems[item.ItemId]; wrapped {
+				break
+			}
+		}
+	}
+	if wrapped {
+		defer mgr.mtx.Unlock()
+		return mgr.scanResult()
+	}
+
+	for _, item := range items {
+		mgr.scanItems[item.ItemId] = struct{}{}
+	}
+	mgr.scanPage++
+	args := ScanProgressArgs{
+		Page:    mgr.scanPage,
+		Items:   len(items),
+		Total:   len(mgr.scanItems),
+		Changed: mgr.scanChanged,
+	}
+	mgr.mtx.Unlock()
+
+	dbg.Printf("scanned page %d (%d items)", args.Page, args.Items)
+	mgr.scanProgress.Dispatch(args)
+	if opts.Progress != nil {
+		opts.Progress(args)
+	}
+
+	if last {
+		mgr.mtx.RLock()
+		defer mgr.mtx.RUnlock()
+		return mgr.scanResult()
+	}
+	if opts.MaxPages > 0 && args.Page >= opts.MaxPages {
+		dbg.Printf("reached page limit (%d)", opts.MaxPages)
+		return ErrScanLimit
+	}
+	return nil
+}
+
+// scanResult returns the cause to complete a finished scan with.
+// The lock must be held.
+func (mgr *Manager) scanResult() error {
+	if mgr.scanChanged {
+		return ErrScanChanged
+	}
+	return ErrScanSuccess
+}
+
+// completeScan completes the scan with the specified cause, if ctx is still the current scan.
+func (mgr *Manager) completeScan(ctx context.Context, done context.CancelCauseFunc, cause error) {
+	mgr.mtx.Lock()
+	if mgr.scanCtx != ctx {
+		mgr.mtx.Unlock()
+		return
+	}
+	var removed []Item
+	if cause == ErrScanSuccess {
+		removed = mgr.pruneItems()
+		mgr.stale = false
+	}
+	dbg.Printf("completing scan (%v)", cause)
+	mgr.scanComplete = cause != ErrScanLimit
+	mgr.mtx.Unlock()
+
+	for _, item := range removed {
+		mgr.itemRemoved.Dispatch(ItemArgs{item})
+	}
+	done(cause)
+}
+
+// pruneItems removes the items that were not found in a completed scan.
+// The lock must be held.
+func (mgr *Manager) pruneItems() (removed []Item) {
+	for id, item := range mgr.items {
+		if _, ok := mgr.scanItems[id]; !ok {
+			delete(mgr.items, id)
+			removed = append(removed, item)
+		}
+	}
+	if len(removed) > 0 {
+		dbg.Printf("removed %d item(s) not found in scan", len(removed))
+	}
+	return
+}
+
+// CancelScan cancels the scan in progress. The scan may be resumed with ResumeScan.
+// Returns false if no scan is in progress.
+func (mgr *Manager) CancelScan() bool {
+	mgr.mtx.Lock()
+	defer mgr.mtx.Unlock()
+
+	if mgr.scanCtx == nil {
+		return false
+	}
+
+	dbg.Printf("cancelling scan")
+
+	mgr.scanDone(context.Canceled)
+	mgr.scanCtx = nil
+	return true
+}
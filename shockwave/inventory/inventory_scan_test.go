@@ -0,0 +1,170 @@
+package inventory
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	g "xabbo.b7c.io/goearth"
+	"xabbo.b7c.io/goearth/internal/testix"
+	"xabbo.b7c.io/goearth/shockwave/in"
+	"xabbo.b7c.io/goearth/shockwave/out"
+)
+
+func scanPage(start, n int) []Item {
+	items := make([]Item, n)
+	for i := range items {
+		items[i] = Item{ItemId: start + i}
+	}
+	return items
+}
+
+func TestProcessScanPage(t *testing.T) {
+	mgr := &Manager{mtx: &sync.RWMutex{}, scanItems: map[int]struct{}{}, scanCtx: context.Background()}
+
+	var progress []ScanProgressArgs
+	opts := ScanOptions{Progress: func(args ScanProgressArgs) { progress = append(progress, args) }}
+
+	if result := mgr.processScanPage(mgr.scanCtx, scanPage(0, PageSize), opts); result != nil {
+		t.Fatalf("unexpected result for full page: %v", result)
+	}
+	mgr.scanChanged = true
+	if result := mgr.processScanPage(mgr.scanCtx, scanPage(PageSize, 3), opts); result != ErrScanChanged {
+		t.Fatalf("incorrect result, expected: %v, actual: %v", ErrScanChanged, result)
+	}
+
+	expected := []ScanProgressArgs{
+		{Page: 1, Items: PageSize, Total: PageSize},
+		{Page: 2, Items: 3, Total: PageSize + 3, Changed: true},
+	}
+	if len(progress) != len(expected) || progress[0] != expected[0] || progress[1] != expected[1] {
+		t.Fatalf("incorrect progress, expected: %+v, actual: %+v", expected, progress)
+	}
+}
+
+func TestProcessScanPageWrapAndLimit(t *testing.T) {
+	mgr := &Manager{mtx: &sync.RWMutex{}, scanItems: map[int]struct{}{}, scanCtx: context.Background()}
+
+	mgr.processScanPage(mgr.scanCtx, scanPage(0, PageSize), ScanOptions{})
+	if result := mgr.processScanPage(mgr.scanCtx, scanPage(0, PageSize), ScanOptions{}); result != ErrScanSuccess {
+		t.Fatalf("incorrect result for wrapped page, expected: %v, actual: %v", ErrScanSuccess, result)
+	}
+	if mgr.scanPage != 1 {
+		t.Fatalf("incorrect page count, expected: 1, actual: %d", mgr.scanPage)
+	}
+
+	if result := mgr.processScanPage(mgr.scanCtx, scanPage(PageSize, PageSize), ScanOptions{MaxPages: 2}); result != ErrScanLimit {
+		t.Fatalf("incorrect result at page limit, expected: %v, actual: %v", ErrScanLimit, result)
+	}
+	mgr.scanCtx = nil
+	if !mgr.canResumeScan() {
+		t.Fatalf("expected scan to be resumable")
+	}
+}
+
+func TestProcessScanPageStale(t *testing.T) {
+	mgr := &Manager{mtx: &sync.RWMutex{}, scanItems: map[int]struct{}{}, scanCtx: context.Background()}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if result := mgr.processScanPage(ctx, scanPage(0, PageSize), ScanOptions{}); result != errScanStale {
+		t.Fatalf("incorrect result for stale page, expected: %v, actual: %v", errScanStale, result)
+	}
+	if mgr.scanPage != 0 || len(mgr.scanItems) != 0 {
+		t.Fatalf("expected stale page not to be recorded, page: %d, items: %d", mgr.scanPage, len(mgr.scanItems))
+	}
+}
+
+// testStrip simulates the server's inventory pages in response to GETSTRIP.
+type testStrip struct {
+	mtx   sync.Mutex
+	items []Item
+	page  int
+	cmds  []string
+}
+
+func (strip *testStrip) respond(ix *testix.Interceptor, pkt *g.Packet) {
+	strip.mtx.Lock()
+	cmd := string(pkt.Data)
+	strip.cmds = append(strip.cmds, cmd)
+	pages := (len(strip.items) + PageSize - 1) / PageSize
+	switch cmd {
+	case "new":
+		strip.page = 0
+	case "next":
+		strip.page = (strip.page + 1) % pages
+	}
+	page := strip.items[strip.page*PageSize : min(len(strip.items), (strip.page+1)*PageSize)]
+	strip.mtx.Unlock()
+
+	values := []any{g.Length(len(page))}
+	for i, item := range page {
+		values = append(values, item.ItemId, i, "S", item.Id, "chair", 1, 1, "")
+	}
+	// respond after the scan has started waiting for the page
+	ix.DispatchLater(in.STRIPINFO_2, values...)
+}
+
+func (strip *testStrip) commands() []string {
+	strip.mtx.Lock()
+	defer strip.mtx.Unlock()
+	return slices.Clone(strip.cmds)
+}
+
+func waitScan(t *testing.T, ctx context.Context) {
+	t.Helper()
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for scan")
+	}
+}
+
+func TestScanCancelResume(t *testing.T) {
+	strip := &testStrip{}
+	for i := range 2*PageSize + 3 {
+		strip.items = append(strip.items, Item{ItemId: i + 1, Id: 100 + i})
+	}
+
+	ix := testix.New(g.Shockwave)
+	defer ix.Close()
+	ix.Resolve(out.GETSTRIP, in.STRIPINFO_2)
+	ix.Respond(out.GETSTRIP, strip.respond)
+	mgr := NewManager(ix)
+
+	opts := ScanOptions{Delay: time.Millisecond, Timeout: time.Second, Retries: 1}
+	cancelOpts := opts
+	cancelOpts.Progress = func(args ScanProgressArgs) {
+		if args.Page == 1 {
+			mgr.CancelScan()
+		}
+	}
+
+	ctx := mgr.ScanWith(cancelOpts)
+	waitScan(t, ctx)
+	if cause := context.Cause(ctx); cause != context.Canceled {
+		t.Fatalf("incorrect scan cause, expected: %v, actual: %v", context.Canceled, cause)
+	}
+	if !mgr.CanResumeScan() {
+		t.Fatalf("expected canceled scan to be resumable")
+	}
+
+	ctx = mgr.ResumeScan(opts)
+	waitScan(t, ctx)
+	if cause := context.Cause(ctx); cause != ErrScanSuccess {
+		t.Fatalf("incorrect scan cause, expected: %v, actual: %v", ErrScanSuccess, cause)
+	}
+	if mgr.ItemCount() != len(strip.items) {
+		t.Fatalf("incorrect item count, expected: %d, actual: %d", len(strip.items), mgr.ItemCount())
+	}
+	if mgr.CanResumeScan() {
+		t.Fatalf("expected completed scan not to be resumable")
+	}
+
+	expected := []string{"new", "next", "next"}
+	if cmds := strip.commands(); !slices.Equal(cmds, expected) {
+		t.Fatalf("incorrect strip commands, expected: %v, actual: %v", expected, cmds)
+	}
+}
@@ -0,0 +1,132 @@
+package inventory
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	g "xabbo.b7c.io/goearth"
+	"xabbo.b7c.io/goearth/internal/testix"
+	"xabbo.b7c.io/goearth/shockwave/in"
+	"xabbo.b7c.io/goearth/shockwave/out"
+)
+
+func testManager(items ...Item) *Manager {
+	mgr := &Manager{mtx: &sync.RWMutex{}, items: map[int]Item{}, placing: map[int]int{}, picking: map[int]struct{}{}}
+	mgr.loadItems(items)
+	return mgr
+}
+
+func newTestManager() (*testix.Interceptor, *Manager) {
+	ix := testix.New(g.Shockwave)
+	mgr := NewManager(ix)
+	// floor item 1 (furni 100) and wall item 2 (furni 200)
+	ix.Dispatch(in.STRIPINFO_2, g.Length(2),
+		1, 0, "S", 100, "chair", 1, 1, "0,0,0",
+		2, 1, "I", 200, "poster", "1")
+	return ix, mgr
+}
+
+func TestPlaceItem(t *testing.T) {
+	ix, mgr := newTestManager()
+	defer ix.Close()
+
+	if mgr.ItemCount() != 2 {
+		t.Fatalf("incorrect item count, expected: 2, actual: %d", mgr.ItemCount())
+	}
+
+	var removed []Item
+	mgr.ItemRemoved(func(e ItemArgs) { removed = append(removed, e.Item) })
+
+	ix.DispatchPacket(ix.NewPacket(out.PLACESTUFF, []byte("1 3 4 1 1 2")))
+	ix.Dispatch(in.ACTIVEOBJECT_ADD, "101")
+	if mgr.Item(1) == nil {
+		t.Fatalf("expected item not to be removed when another object is added")
+	}
+	ix.Dispatch(in.ACTIVEOBJECT_ADD, "100")
+
+	if mgr.Item(1) != nil || mgr.Item(2) == nil {
+		t.Fatalf("incorrect items after placement: %v", mgr.items)
+	}
+	if len(removed) != 1 || removed[0].ItemId != 1 {
+		t.Fatalf("incorrect removed items: %v", removed)
+	}
+
+	ix.DispatchPacket(ix.NewPacket(out.PLACESTUFF, []byte("2 :w=3,0 l=12,45 l")))
+	ix.Dispatch(in.ITEMS_2, "200\tposter\towner\t:w=3,0 l=12,45 l")
+	if mgr.Item(2) != nil {
+		t.Fatalf("expected placed wall item to be removed")
+	}
+}
+
+func TestPlaceItemRoomChange(t *testing.T) {
+	ix, mgr := newTestManager()
+	defer ix.Close()
+
+	ix.DispatchPacket(ix.NewPacket(out.PLACESTUFF, []byte("1 3 4 1 1 2")))
+	ix.Dispatch(in.CLC)
+	ix.Dispatch(in.ACTIVEOBJECT_ADD, "100")
+
+	if mgr.Item(1) == nil {
+		t.Fatalf("expected pending placement to be cleared on room change")
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	ix, mgr := newTestManager()
+	defer ix.Close()
+
+	var added []Item
+	mgr.ItemAdded(func(e ItemArgs) { added = append(added, e.Item) })
+
+	ix.DispatchPacket(ix.NewPacket(out.ADDSTRIPITEM, []byte("new stuff 300")))
+	if sent := ix.SentTo(out.GETSTRIP); len(sent) != 0 {
+		t.Fatalf("expected no page request before the furni is removed, actual: %d", len(sent))
+	}
+	ix.Dispatch(in.ACTIVEOBJECT_REMOVE, "300")
+
+	ix.Dispatch(in.PURCHASE_OK)
+
+	sent := ix.SentTo(out.GETSTRIP)
+	if len(sent) != 2 || string(sent[0].Data) != "update" || string(sent[1].Data) != "update" {
+		t.Fatalf("incorrect page requests: %v", sent)
+	}
+	if !mgr.Stale() {
+		t.Fatalf("expected inventory to be stale")
+	}
+
+	ix.Dispatch(in.STRIPINFO_2, g.Length(2),
+		1, 0, "S", 100, "chair", 1, 1, "0,0,0",
+		3, 1, "S", 300, "table", 2, 2, "0,0,0")
+	if len(added) != 1 || added[0].ItemId != 3 {
+		t.Fatalf("incorrect added items: %v", added)
+	}
+}
+
+func TestInvalidate(t *testing.T) {
+	mgr := testManager(Item{ItemId: 1}, Item{ItemId: 2})
+
+	invalidated := 0
+	mgr.Invalidated(func() { invalidated++ })
+
+	mgr.invalidate("test")
+	mgr.invalidate("test")
+	if !mgr.Stale() || invalidated != 1 {
+		t.Fatalf("incorrect invalidation, expected stale once, actual: stale %t, %d time(s)", mgr.Stale(), invalidated)
+	}
+
+	mgr.scanItems = map[int]struct{}{1: {}}
+	ctx, done := context.WithCancelCause(context.Background())
+	mgr.scanCtx = ctx
+	mgr.completeScan(ctx, done, ErrScanSuccess)
+
+	if mgr.Stale() {
+		t.Fatalf("expected inventory not to be stale after a successful scan")
+	}
+	if mgr.Item(2) != nil || mgr.ItemCount() != 1 {
+		t.Fatalf("expected item not found in scan to be removed")
+	}
+	if cause := context.Cause(ctx); cause != ErrScanSuccess {
+		t.Fatalf("incorrect scan cause, expected: %v, actual: %v", ErrScanSuccess, cause)
+	}
+}
@@ -8,6 +8,7 @@ type Args struct {
 }
 
 // Updated registers an event handler that is invoked when the user's profile is updated.
-func (mgr *Manager) Updated(handler g.EventHandler[Args]) {
-	mgr.updated.Register(handler)
+func (mgr *Manager) Updated(handlers ...g.EventHandler[Args]) *g.Event[Args] {
+	mgr.updated.Register(handlers...)
+	return &mgr.updated
 }
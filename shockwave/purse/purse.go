@@ -0,0 +1,26 @@
+package purse
+
+import (
+	"strconv"
+	"strings"
+
+	g "xabbo.b7c.io/goearth"
+)
+
+// Credits represents the user's credit balance.
+// It is sent as a decimal string by the server, e.g. "100.0".
+type Credits int
+
+func (credits *Credits) Parse(p *g.Packet, pos *int) {
+	s := p.ReadStringPtr(pos)
+	// Balances are sent with a fractional part which is always zero.
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		s = s[:i]
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		dbg.Printf("WARNING: invalid credit balance: %q", s)
+		return
+	}
+	*credits = Credits(n)
+}
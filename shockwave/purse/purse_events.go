@@ -0,0 +1,22 @@
+package purse
+
+import g "xabbo.b7c.io/goearth"
+
+// Args holds the arguments for purse events.
+type Args struct {
+	Credits int
+	Tickets int
+	Film    int
+	// CreditsDelta, TicketsDelta and FilmDelta hold the change in each balance since the previous update.
+	// They are zero when a balance is received for the first time.
+	CreditsDelta int
+	TicketsDelta int
+	FilmDelta    int
+}
+
+// Changed registers an event handler that is invoked when the user's credits, tickets or film change,
+// or when a balance is received for the first time.
+func (mgr *Manager) Changed(handlers ...g.EventHandler[Args]) *g.Event[Args] {
+	mgr.changed.Register(handlers...)
+	return &mgr.changed
+}
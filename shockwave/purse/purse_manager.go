@@ -0,0 +1,170 @@
+package purse
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	g "xabbo.b7c.io/goearth"
+	"xabbo.b7c.io/goearth/internal/debug"
+	"xabbo.b7c.io/goearth/shockwave/in"
+	"xabbo.b7c.io/goearth/shockwave/out"
+	"xabbo.b7c.io/goearth/shockwave/profile"
+)
+
+var dbg = debug.NewLogger("[purse]")
+
+// ErrTimeout is returned when a purse request does not receive a response in time.
+var ErrTimeout = errors.New("purse request timed out")
+
+// Manager tracks the user's credits, tickets and film.
+type Manager struct {
+	ix      g.Interceptor
+	changed g.Event[Args]
+
+	mtx     *sync.RWMutex
+	credits balance
+	tickets balance
+	film    balance
+}
+
+// balance holds a balance and whether it has been received.
+type balance struct {
+	value  int
+	loaded bool
+}
+
+// set sets the balance and returns the change since the previous value.
+// The change is zero if the balance had not been received yet.
+func (bal *balance) set(value int) (delta int) {
+	if bal.loaded {
+		delta = value - bal.value
+	}
+	bal.value, bal.loaded = value, true
+	return
+}
+
+// NewManager creates a new purse Manager using the provided extension.
+func NewManager(ix g.Interceptor) *Manager {
+	mgr := &Manager{
+		ix:  ix,
+		mtx: &sync.RWMutex{},
+	}
+	ix.Intercept(in.PURSE).With(mgr.handlePurse)
+	ix.Intercept(in.PURSE_2).With(mgr.handlePurse2)
+	ix.Intercept(in.TICKETS, in.TICKETSBUY).With(mgr.handleTickets)
+	ix.Intercept(in.USER_OBJ).With(mgr.handleUserObj)
+	return mgr
+}
+
+// Credits returns the user's credit balance.
+func (mgr *Manager) Credits() int {
+	mgr.mtx.RLock()
+	defer mgr.mtx.RUnlock()
+	return mgr.credits.value
+}
+
+// Tickets returns the user's ticket balance.
+func (mgr *Manager) Tickets() int {
+	mgr.mtx.RLock()
+	defer mgr.mtx.RUnlock()
+	return mgr.tickets.value
+}
+
+// Film returns the user's photo film balance.
+func (mgr *Manager) Film() int {
+	mgr.mtx.RLock()
+	defer mgr.mtx.RUnlock()
+	return mgr.film.value
+}
+
+// Refresh requests the user's credit balance and waits for the response.
+// The ticket and film balances are not requested; they are updated as the server sends them.
+// Returns the cause of the context's cancellation if it is done before the balance is received,
+// or ErrTimeout if no response is received.
+func (mgr *Manager) Refresh(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// The balance is updated by the purse handler, which is invoked before this receiver.
+	recv := mgr.ix.Recv(in.PURSE)
+	context.AfterFunc(ctx, recv.Cancel)
+	ch := recv.Await()
+
+	mgr.ix.Send(out.GET_CREDITS)
+	dbg.Println("requested credits")
+
+	select {
+	case pkt := <-ch:
+		if pkt == nil {
+			if err := context.Cause(ctx); err != nil {
+				return err
+			}
+			return ErrTimeout
+		}
+		return nil
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
+
+// update applies the specified changes to the balances under the lock,
+// and dispatches the changed event if any balance changed or was received for the first time.
+func (mgr *Manager) update(apply func(args *Args)) {
+	mgr.mtx.Lock()
+	loaded := mgr.loaded()
+	args := Args{}
+	apply(&args)
+	args.Credits = mgr.credits.value
+	args.Tickets = mgr.tickets.value
+	args.Film = mgr.film.value
+	changed := mgr.loaded() != loaded ||
+		args.CreditsDelta != 0 || args.TicketsDelta != 0 || args.FilmDelta != 0
+	mgr.mtx.Unlock()
+
+	if changed {
+		dbg.Printf("purse changed (credits: %d (%+d), tickets: %d (%+d), film: %d (%+d))",
+			args.Credits, args.CreditsDelta, args.Tickets, args.TicketsDelta, args.Film, args.FilmDelta)
+		mgr.changed.Dispatch(args)
+	}
+}
+
+// loaded returns which balances have been received.
+// The caller must hold the lock.
+func (mgr *Manager) loaded() [3]bool {
+	return [3]bool{mgr.credits.loaded, mgr.tickets.loaded, mgr.film.loaded}
+}
+
+// handlers
+
+func (mgr *Manager) handlePurse(e *g.Intercept) {
+	var credits Credits
+	e.Packet.Read(&credits)
+	mgr.update(func(args *Args) {
+		args.CreditsDelta = mgr.credits.set(int(credits))
+	})
+}
+
+func (mgr *Manager) handlePurse2(e *g.Intercept) {
+	tickets, film := e.Packet.ReadInt(), e.Packet.ReadInt()
+	mgr.update(func(args *Args) {
+		args.TicketsDelta = mgr.tickets.set(tickets)
+		args.FilmDelta = mgr.film.set(film)
+	})
+}
+
+func (mgr *Manager) handleTickets(e *g.Intercept) {
+	tickets := e.Packet.ReadInt()
+	mgr.update(func(args *Args) {
+		args.TicketsDelta = mgr.tickets.set(tickets)
+	})
+}
+
+func (mgr *Manager) handleUserObj(e *g.Intercept) {
+	var profile profile.Profile
+	e.Packet.Read(&profile)
+	mgr.update(func(args *Args) {
+		args.TicketsDelta = mgr.tickets.set(profile.PhTickets)
+		args.FilmDelta = mgr.film.set(profile.PhotoFilm)
+	})
+}
@@ -0,0 +1,106 @@
+package purse
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	g "xabbo.b7c.io/goearth"
+	"xabbo.b7c.io/goearth/internal/testix"
+	"xabbo.b7c.io/goearth/shockwave/in"
+	"xabbo.b7c.io/goearth/shockwave/out"
+)
+
+func newPurseTest() (*testix.Interceptor, *Manager, *[]Args) {
+	ix := testix.New(g.Shockwave)
+	ix.Resolve(out.GET_CREDITS, in.PURSE, in.PURSE_2, in.TICKETS, in.TICKETSBUY, in.USER_OBJ)
+	mgr := NewManager(ix)
+	events := &[]Args{}
+	mgr.Changed(func(args Args) { *events = append(*events, args) })
+	return ix, mgr, events
+}
+
+func TestCredits(t *testing.T) {
+	tests := []struct {
+		data     string
+		expected Credits
+	}{
+		{"100.0", 100},
+		{"0.0", 0},
+		{"2500", 2500},
+	}
+
+	for _, test := range tests {
+		var credits Credits
+		pkt := &g.Packet{Client: g.Shockwave, Header: g.Header{Dir: g.In}}
+		pkt.WriteString(test.data)
+		pkt.Pos = 0
+		pkt.Read(&credits)
+		if credits != test.expected {
+			t.Errorf("incorrect credits for %q, expected: %d, actual: %d", test.data, test.expected, credits)
+		}
+	}
+}
+
+func TestChanged(t *testing.T) {
+	ix, mgr, events := newPurseTest()
+	defer ix.Close()
+
+	ix.Dispatch(in.PURSE, "100.0")
+	ix.Dispatch(in.USER_OBJ, "name=user\rph_tickets=5\rphoto_film=2")
+	ix.Dispatch(in.PURSE, "100.0")
+	ix.Dispatch(in.PURSE, "90.0")
+	ix.Dispatch(in.TICKETSBUY, 7)
+	ix.Dispatch(in.PURSE_2, 7, 1)
+
+	expected := []Args{
+		{Credits: 100},
+		{Credits: 100, Tickets: 5, Film: 2},
+		{Credits: 90, Tickets: 5, Film: 2, CreditsDelta: -10},
+		{Credits: 90, Tickets: 7, Film: 2, TicketsDelta: 2},
+		{Credits: 90, Tickets: 7, Film: 1, FilmDelta: -1},
+	}
+	if len(*events) != len(expected) {
+		t.Fatalf("incorrect number of events, expected: %d, actual: %d (%+v)", len(expected), len(*events), *events)
+	}
+	for i := range expected {
+		if (*events)[i] != expected[i] {
+			t.Errorf("incorrect event %d, expected: %+v, actual: %+v", i, expected[i], (*events)[i])
+		}
+	}
+
+	if mgr.Credits() != 90 || mgr.Tickets() != 7 || mgr.Film() != 1 {
+		t.Errorf("incorrect balances, credits: %d, tickets: %d, film: %d", mgr.Credits(), mgr.Tickets(), mgr.Film())
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	ix, mgr, _ := newPurseTest()
+	defer ix.Close()
+
+	ix.Respond(out.GET_CREDITS, func(ix *testix.Interceptor, _ *g.Packet) {
+		ix.DispatchLater(in.PURSE, "250.0")
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := mgr.Refresh(ctx); err != nil {
+		t.Fatalf("failed to refresh: %v", err)
+	}
+	if mgr.Credits() != 250 {
+		t.Errorf("incorrect credits, expected: %d, actual: %d", 250, mgr.Credits())
+	}
+}
+
+func TestRefreshCanceled(t *testing.T) {
+	ix, mgr, _ := newPurseTest()
+	defer ix.Close()
+
+	// the credit balance is never received
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := mgr.Refresh(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("incorrect error, expected: %v, actual: %v", context.DeadlineExceeded, err)
+	}
+}
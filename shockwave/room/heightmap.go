@@ -0,0 +1,350 @@
+package room
+
+import (
+	"cmp"
+	"container/heap"
+	"slices"
+	"strings"
+
+	g "xabbo.b7c.io/goearth"
+)
+
+// Default height limits used when finding a path.
+const (
+	// DefaultMaxStepUp is the maximum height an entity can step up between two tiles.
+	DefaultMaxStepUp = 1.5
+	// DefaultMaxStepDown is the maximum height an entity can step down between two tiles.
+	DefaultMaxStepDown = 4.0
+)
+
+// HeightmapTile represents the state of a single tile in a heightmap.
+type HeightmapTile struct {
+	Height float64
+	// Blocked indicates whether the tile cannot be walked on.
+	Blocked bool
+}
+
+// Heightmap represents the floor of a room.
+// Tiles are indexed by their X and Y coordinates, where Y is the line in the heightmap string.
+type Heightmap struct {
+	Width, Length int
+	tiles         []HeightmapTile
+}
+
+// ParseHeightmap parses a heightmap from a string of carriage return-separated lines.
+// Each character represents a tile, where 'x' is a blocked tile, and 0-9 and a-z represent heights 0-35.
+func ParseHeightmap(s string) *Heightmap {
+	lines := strings.Split(strings.TrimRight(s, "\r\n"), "\r")
+	hm := &Heightmap{Length: len(lines)}
+	for _, line := range lines {
+		hm.Width = max(hm.Width, len(line))
+	}
+	hm.tiles = make([]HeightmapTile, hm.Width*hm.Length)
+	for y, line := range lines {
+		for x := range hm.Width {
+			tile := &hm.tiles[y*hm.Width+x]
+			if x >= len(line) {
+				tile.Blocked = true
+				continue
+			}
+			switch c := line[x]; {
+			case c >= '0' && c <= '9':
+				tile.Height = float64(c - '0')
+			case c >= 'a' && c <= 'w', c >= 'y' && c <= 'z':
+				tile.Height = float64(c-'a') + 10
+			default:
+				tile.Blocked = true
+			}
+		}
+	}
+	return hm
+}
+
+func (hm *Heightmap) Parse(p *g.Packet, pos *int) {
+	*hm = *ParseHeightmap(p.ReadStringPtr(pos))
+}
+
+// String returns the heightmap as a string of carriage return-separated lines.
+// Heights are rounded down to the nearest integer.
+func (hm *Heightmap) String() string {
+	var sb strings.Builder
+	for y := range hm.Length {
+		if y > 0 {
+			sb.WriteByte('\r')
+		}
+		for x := range hm.Width {
+			tile := hm.tiles[y*hm.Width+x]
+			switch h := int(tile.Height); {
+			case tile.Blocked:
+				sb.WriteByte('x')
+			case h < 10:
+				sb.WriteByte('0' + byte(h))
+			default:
+				sb.WriteByte('a' + byte(min(h, 35)-10))
+			}
+		}
+	}
+	return sb.String()
+}
+
+// Clone returns a copy of the heightmap.
+func (hm *Heightmap) Clone() *Heightmap {
+	clone := *hm
+	clone.tiles = make([]HeightmapTile, len(hm.tiles))
+	copy(clone.tiles, hm.tiles)
+	return &clone
+}
+
+// InBounds returns whether the specified coordinates are within the bounds of the heightmap.
+func (hm *Heightmap) InBounds(x, y int) bool {
+	return x >= 0 && y >= 0 && x < hm.Width && y < hm.Length
+}
+
+// At returns the tile at the specified coordinates.
+// Tiles outside the bounds of the heightmap are blocked.
+func (hm *Heightmap) At(x, y int) HeightmapTile {
+	if !hm.InBounds(x, y) {
+		return HeightmapTile{Blocked: true}
+	}
+	return hm.tiles[y*hm.Width+x]
+}
+
+// Set sets the tile at the specified coordinates.
+// Coordinates outside the bounds of the heightmap are ignored.
+func (hm *Heightmap) Set(x, y int, tile HeightmapTile) {
+	if hm.InBounds(x, y) {
+		hm.tiles[y*hm.Width+x] = tile
+	}
+}
+
+// Block marks the tile at the specified coordinates as blocked.
+func (hm *Heightmap) Block(x, y int) {
+	if hm.InBounds(x, y) {
+		hm.tiles[y*hm.Width+x].Blocked = true
+	}
+}
+
+// IsWalkable returns whether the tile at the specified coordinates can be walked on.
+func (hm *Heightmap) IsWalkable(x, y int) bool {
+	return !hm.At(x, y).Blocked
+}
+
+// BlockObject marks the tiles occupied by the specified floor item as blocked.
+func (hm *Heightmap) BlockObject(obj Object) {
+	for _, pt := range obj.Footprint() {
+		hm.Block(pt.X, pt.Y)
+	}
+}
+
+// ObjectInfo describes how a floor item affects the tiles it occupies.
+type ObjectInfo struct {
+	// Walkable indicates whether entities can walk over the item, such as a rug.
+	Walkable bool
+	// StackHeight is the height of the item's surface above its base.
+	// Items stacked on top of it, and entities walking over it, are raised by this amount.
+	StackHeight float64
+}
+
+// ObjectInfoFunc returns the ObjectInfo of a floor item, typically looked up by its class.
+type ObjectInfoFunc func(obj Object) ObjectInfo
+
+// PlaceObjects updates the tiles occupied by the specified floor items.
+// The topmost item on a tile determines its height, which is the item's Z plus its stack height,
+// and whether the tile can be walked on. Void tiles remain blocked.
+// If info is nil, every item is treated as non-walkable with no stack height.
+func (hm *Heightmap) PlaceObjects(objs []Object, info ObjectInfoFunc) {
+	type placement struct {
+		obj Object
+		ObjectInfo
+	}
+	placements := make([]placement, 0, len(objs))
+	for _, obj := range objs {
+		p := placement{obj: obj}
+		if info != nil {
+			p.ObjectInfo = info(obj)
+		}
+		placements = append(placements, p)
+	}
+	slices.SortStableFunc(placements, func(a, b placement) int {
+		if c := cmp.Compare(a.obj.Z+a.StackHeight, b.obj.Z+b.StackHeight); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.obj.Z, b.obj.Z)
+	})
+
+	void := make([]bool, len(hm.tiles))
+	for i, tile := range hm.tiles {
+		void[i] = tile.Blocked
+	}
+	for _, p := range placements {
+		for _, pt := range p.obj.Footprint() {
+			if !hm.InBounds(pt.X, pt.Y) || void[pt.Y*hm.Width+pt.X] {
+				continue
+			}
+			hm.Set(pt.X, pt.Y, HeightmapTile{
+				Height:  p.obj.Z + p.StackHeight,
+				Blocked: !p.Walkable,
+			})
+		}
+	}
+}
+
+// Footprint returns the points occupied by the object,
+// taking into account its dimensions and direction.
+func (obj Object) Footprint() []Point {
+	w, l := obj.Width, obj.Height
+	if obj.Direction == 0 || obj.Direction == 4 {
+		w, l = l, w
+	}
+	w, l = max(w, 1), max(l, 1)
+	points := make([]Point, 0, w*l)
+	for y := obj.Y; y < obj.Y+l; y++ {
+		for x := obj.X; x < obj.X+w; x++ {
+			points = append(points, Point{x, y})
+		}
+	}
+	return points
+}
+
+// PathOptions holds the options used when finding a path.
+type PathOptions struct {
+	// MaxStepUp and MaxStepDown are the maximum height differences between two adjacent tiles.
+	MaxStepUp, MaxStepDown float64
+	// NoDiagonal disables diagonal movement.
+	NoDiagonal bool
+}
+
+// DefaultPathOptions returns the path options matching the client's movement rules.
+func DefaultPathOptions() PathOptions {
+	return PathOptions{
+		MaxStepUp:   DefaultMaxStepUp,
+		MaxStepDown: DefaultMaxStepDown,
+	}
+}
+
+// FindPath finds the shortest path between two points using the A* algorithm with the default path options.
+// The returned path excludes the starting point and includes the destination.
+// Returns nil if no path exists.
+func (hm *Heightmap) FindPath(from, to Point) []Point {
+	return hm.FindPathWith(from, to, DefaultPathOptions())
+}
+
+// CanReach returns whether a path exists between two points with the default path options.
+func (hm *Heightmap) CanReach(from, to Point) bool {
+	return from == to || hm.FindPath(from, to) != nil
+}
+
+// FindPathWith finds the shortest path between two points using the A* algorithm with the specified options.
+//
+// As in the client, diagonal moves are not allowed to cut the corner of a blocked tile,
+// so a diagonal step requires both adjacent orthogonal tiles to be walkable.
+func (hm *Heightmap) FindPathWith(from, to Point, opts PathOptions) []Point {
+	if from == to || !hm.InBounds(from.X, from.Y) || !hm.IsWalkable(to.X, to.Y) {
+		return nil
+	}
+
+	index := func(pt Point) int { return pt.Y*hm.Width + pt.X }
+	cost := make([]int, len(hm.tiles))
+	parent := make([]int, len(hm.tiles))
+	for i := range cost {
+		cost[i] = -1
+	}
+
+	open := &pathQueue{}
+	start := index(from)
+	cost[start] = 0
+	parent[start] = -1
+	heap.Push(open, pathNode{from, chebyshev(from, to)})
+
+	for open.Len() > 0 {
+		cur := heap.Pop(open).(pathNode)
+		if cur.pt == to {
+			break
+		}
+		curIndex := index(cur.pt)
+		curTile := hm.tiles[curIndex]
+		for _, dir := range pathDirections {
+			diagonal := dir.X != 0 && dir.Y != 0
+			if diagonal && opts.NoDiagonal {
+				continue
+			}
+			next := Point{cur.pt.X + dir.X, cur.pt.Y + dir.Y}
+			if !hm.IsWalkable(next.X, next.Y) {
+				continue
+			}
+			if diagonal && (!hm.IsWalkable(cur.pt.X+dir.X, cur.pt.Y) || !hm.IsWalkable(cur.pt.X, cur.pt.Y+dir.Y)) {
+				continue
+			}
+			nextIndex := index(next)
+			diff := hm.tiles[nextIndex].Height - curTile.Height
+			if diff > opts.MaxStepUp || -diff > opts.MaxStepDown {
+				continue
+			}
+			nextCost := cost[curIndex] + 1
+			if cost[nextIndex] >= 0 && cost[nextIndex] <= nextCost {
+				continue
+			}
+			cost[nextIndex] = nextCost
+			parent[nextIndex] = curIndex
+			heap.Push(open, pathNode{next, nextCost + chebyshev(next, to)})
+		}
+	}
+
+	end := index(to)
+	if cost[end] < 0 {
+		return nil
+	}
+	path := make([]Point, cost[end])
+	for i := end; i != start; i = parent[i] {
+		path[cost[i]-1] = Point{i % hm.Width, i / hm.Width}
+	}
+	return path
+}
+
+// pathDirections lists the orthogonal directions before the diagonal ones,
+// so that straight moves are preferred when costs are equal.
+var pathDirections = []Point{
+	{0, -1}, {1, 0}, {0, 1}, {-1, 0},
+	{1, -1}, {1, 1}, {-1, 1}, {-1, -1},
+}
+
+func chebyshev(a, b Point) int {
+	return max(abs(a.X-b.X), abs(a.Y-b.Y))
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+type pathNode struct {
+	pt       Point
+	priority int
+}
+
+type pathQueue []pathNode
+
+func (q pathQueue) Len() int           { return len(q) }
+func (q pathQueue) Less(i, j int) bool { return q[i].priority < q[j].priority }
+func (q pathQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *pathQueue) Push(x any)        { *q = append(*q, x.(pathNode)) }
+func (q *pathQueue) Pop() any {
+	old := *q
+	n := len(old)
+	node := old[n-1]
+	*q = old[:n-1]
+	return node
+}
+
+// MarshalText encodes the heightmap as its string representation.
+func (hm *Heightmap) MarshalText() ([]byte, error) {
+	return []byte(hm.String()), nil
+}
+
+// UnmarshalText decodes the heightmap from its string representation.
+func (hm *Heightmap) UnmarshalText(text []byte) error {
+	*hm = *ParseHeightmap(string(text))
+	return nil
+}
@@ -0,0 +1,114 @@
+package room
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHeightmap(t *testing.T) {
+	s := "xx00\r0012\r00a"
+	hm := ParseHeightmap(s)
+
+	if hm.Width != 4 || hm.Length != 3 {
+		t.Fatalf("incorrect heightmap size, expected: %dx%d, actual: %dx%d", 4, 3, hm.Width, hm.Length)
+	}
+	if tile := hm.At(2, 2); tile.Height != 10 || tile.Blocked {
+		t.Fatalf("incorrect tile, expected: %+v, actual: %+v", HeightmapTile{Height: 10}, tile)
+	}
+	if !hm.At(0, 0).Blocked || !hm.At(3, 2).Blocked || !hm.At(-1, 0).Blocked {
+		t.Fatal("expected void tiles to be blocked")
+	}
+	if expected := "xx00\r0012\r00ax"; hm.String() != expected {
+		t.Fatalf("incorrect heightmap string, expected: %q, actual: %q", expected, hm.String())
+	}
+}
+
+func TestFindPath(t *testing.T) {
+	hm := ParseHeightmap("00000\r0x0x0\r00000")
+
+	path := hm.FindPath(Point{0, 0}, Point{4, 2})
+	if len(path) != 6 || path[len(path)-1] != (Point{4, 2}) {
+		t.Fatalf("incorrect path: %v", path)
+	}
+
+	// diagonal moves may not cut the corner of a blocked tile
+	path = hm.FindPath(Point{0, 0}, Point{2, 2})
+	expected := []Point{{1, 0}, {2, 0}, {2, 1}, {2, 2}}
+	if !reflect.DeepEqual(expected, path) {
+		t.Fatalf("incorrect path, expected: %v, actual: %v", expected, path)
+	}
+
+	if hm.FindPath(Point{0, 0}, Point{1, 1}) != nil {
+		t.Fatal("expected no path to a blocked tile")
+	}
+}
+
+func TestFindPathHeight(t *testing.T) {
+	hm := ParseHeightmap("020\r020\r000")
+
+	path := hm.FindPath(Point{0, 0}, Point{2, 0})
+	expected := []Point{{0, 1}, {1, 2}, {2, 1}, {2, 0}}
+	if !reflect.DeepEqual(expected, path) {
+		t.Fatalf("incorrect path, expected: %v, actual: %v", expected, path)
+	}
+}
+
+func TestObjectFootprint(t *testing.T) {
+	hm := ParseHeightmap("000\r000\r000")
+	hm.BlockObject(Object{X: 0, Y: 0, Width: 2, Height: 1, Direction: 2})
+	hm.BlockObject(Object{X: 2, Y: 1, Width: 2, Height: 1, Direction: 0})
+
+	if expected := "xx0\r00x\r00x"; hm.String() != expected {
+		t.Fatalf("incorrect heightmap, expected: %q, actual: %q", expected, hm.String())
+	}
+}
+
+func TestWalkMap(t *testing.T) {
+	mgr := FromSnapshot(&Snapshot{
+		Id:        1,
+		Heightmap: ParseHeightmap("000\r000\r000"),
+		Objects: []Object{
+			{Id: 1, Class: "rug", X: 0, Y: 0, Width: 3, Height: 1, Direction: 2},
+			{Id: 2, Class: "table", X: 1, Y: 1, Width: 1, Height: 1},
+			{Id: 3, Class: "stack_tile", X: 1, Y: 1, Width: 1, Height: 1, Z: 1},
+			{Id: 4, Class: "table", X: 2, Y: 2, Width: 1, Height: 1},
+			{Id: 5, Class: "chair", X: 0, Y: 2, Width: 1, Height: 1},
+		},
+	})
+	mgr.SetObjectInfo(func(obj Object) ObjectInfo {
+		switch obj.Class {
+		case "rug":
+			return ObjectInfo{Walkable: true}
+		case "table":
+			return ObjectInfo{StackHeight: 1}
+		case "stack_tile":
+			return ObjectInfo{Walkable: true, StackHeight: 0.5}
+		case "chair":
+			return ObjectInfo{StackHeight: 0.5}
+		}
+		return ObjectInfo{}
+	})
+
+	hm := mgr.WalkMap()
+	tests := []struct {
+		pt       Point
+		expected HeightmapTile
+	}{
+		{Point{1, 0}, HeightmapTile{Height: 0}},
+		{Point{1, 1}, HeightmapTile{Height: 1.5}},
+		{Point{2, 2}, HeightmapTile{Height: 1, Blocked: true}},
+		{Point{0, 2}, HeightmapTile{Height: 0.5, Blocked: true}},
+	}
+	for _, test := range tests {
+		if actual := hm.At(test.pt.X, test.pt.Y); actual != test.expected {
+			t.Errorf("incorrect tile at %v, expected: %+v, actual: %+v", test.pt, test.expected, actual)
+		}
+	}
+
+	if !mgr.CanReach(Point{0, 0}, Point{2, 0}) {
+		t.Fatal("expected the rug to be walkable")
+	}
+	if path := mgr.FindPath(Point{0, 1}, Point{0, 2}); len(path) != 1 {
+		t.Fatalf("expected the chair to be reachable as a destination, path: %v", path)
+	}
+}
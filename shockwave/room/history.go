@@ -0,0 +1,236 @@
+package room
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// UserRecord holds the information recorded about a user seen in a room.
+type UserRecord struct {
+	Name      string    `json:"name"`
+	Figure    string    `json:"figure"`
+	Gender    string    `json:"gender"`
+	Custom    string    `json:"custom"`
+	BadgeCode string    `json:"badge,omitempty"`
+	RoomId    int       `json:"roomId"`
+	FirstSeen time.Time `json:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen"`
+}
+
+// HistoryStore stores the records of users seen in rooms.
+// Names are case-insensitive.
+type HistoryStore interface {
+	// Get gets the record of the user with the specified name.
+	Get(name string) (rec UserRecord, ok bool, err error)
+	// Put stores the record of a user, replacing any existing record with the same name.
+	Put(rec UserRecord) error
+	// PutAll stores the records of multiple users at once.
+	PutAll(recs []UserRecord) error
+}
+
+// SetHistoryStore sets the store used to record the users seen in rooms.
+// Users are recorded as they are loaded or enter the room, and their last seen time
+// is updated when they leave. A nil store disables recording.
+// Records are stored in the background, see FlushHistory.
+func (mgr *Manager) SetHistoryStore(store HistoryStore) {
+	mgr.mtxHist.Lock()
+	defer mgr.mtxHist.Unlock()
+	mgr.history = store
+}
+
+// FlushHistory waits until all queued records have been stored.
+func (mgr *Manager) FlushHistory() {
+	mgr.mtxHist.RLock()
+	flushed := mgr.histFlushed
+	mgr.mtxHist.RUnlock()
+	if flushed != nil {
+		<-flushed
+	}
+}
+
+// recordHistory queues the users in the specified entities to be recorded, if a history store is set.
+// The records are stored by a background writer, so that the caller is never blocked by the store.
+func (mgr *Manager) recordHistory(roomId int, ents []Entity) {
+	mgr.mtxHist.Lock()
+	defer mgr.mtxHist.Unlock()
+	if mgr.history == nil {
+		return
+	}
+
+	now := time.Now()
+	n := len(mgr.histQueue)
+	for _, ent := range ents {
+		if ent.Type != User {
+			continue
+		}
+		mgr.histQueue = append(mgr.histQueue, UserRecord{
+			Name:      ent.Name,
+			Figure:    ent.Figure,
+			Gender:    ent.Gender,
+			Custom:    ent.Custom,
+			BadgeCode: ent.BadgeCode,
+			RoomId:    roomId,
+			FirstSeen: now,
+			LastSeen:  now,
+		})
+	}
+	if len(mgr.histQueue) > n && mgr.histFlushed == nil {
+		mgr.histFlushed = make(chan struct{})
+		go mgr.writeHistory(mgr.histFlushed)
+	}
+}
+
+// writeHistory stores the queued records until the queue is empty, then closes flushed.
+// Records queued while a batch is being stored are stored together in the next batch.
+func (mgr *Manager) writeHistory(flushed chan struct{}) {
+	for {
+		mgr.mtxHist.Lock()
+		store, queue := mgr.history, mgr.histQueue
+		mgr.histQueue = nil
+		if len(queue) == 0 || store == nil {
+			mgr.histFlushed = nil
+			mgr.mtxHist.Unlock()
+			close(flushed)
+			return
+		}
+		mgr.mtxHist.Unlock()
+
+		storeHistory(store, queue)
+	}
+}
+
+// storeHistory stores the records with a single call to PutAll, keeping the first seen time
+// of existing records. If a user appears more than once, their latest record is stored.
+func storeHistory(store HistoryStore, queue []UserRecord) {
+	index := map[string]int{}
+	recs := make([]UserRecord, 0, len(queue))
+	for _, rec := range queue {
+		key := strings.ToLower(rec.Name)
+		if i, ok := index[key]; ok {
+			rec.FirstSeen = recs[i].FirstSeen
+			recs[i] = rec
+			continue
+		}
+		existing, ok, err := store.Get(rec.Name)
+		if err != nil {
+			dbg.Printf("WARNING: failed to get user history for %q: %v", rec.Name, err)
+			continue
+		}
+		if ok {
+			rec.FirstSeen = existing.FirstSeen
+		}
+		index[key] = len(recs)
+		recs = append(recs, rec)
+	}
+	if len(recs) == 0 {
+		return
+	}
+	if err := store.PutAll(recs); err != nil {
+		dbg.Printf("WARNING: failed to record user history for %d users: %v", len(recs), err)
+	}
+}
+
+// FileHistory is a HistoryStore that keeps records in memory and saves them to a JSON file.
+type FileHistory struct {
+	path    string
+	mtx     *sync.RWMutex
+	records map[string]UserRecord
+}
+
+// NewFileHistory creates a history store backed by the JSON file at the specified path,
+// loading any existing records. The file is created when the first record is stored.
+func NewFileHistory(path string) (*FileHistory, error) {
+	h := &FileHistory{
+		path:    path,
+		mtx:     &sync.RWMutex{},
+		records: map[string]UserRecord{},
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return h, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var records []UserRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, err
+	}
+	for _, rec := range records {
+		h.records[strings.ToLower(rec.Name)] = rec
+	}
+	return h, nil
+}
+
+// Get gets the record of the user with the specified name.
+func (h *FileHistory) Get(name string) (UserRecord, bool, error) {
+	h.mtx.RLock()
+	defer h.mtx.RUnlock()
+	rec, ok := h.records[strings.ToLower(name)]
+	return rec, ok, nil
+}
+
+// Put stores the record of a user and saves the records to the file.
+func (h *FileHistory) Put(rec UserRecord) error {
+	return h.PutAll([]UserRecord{rec})
+}
+
+// PutAll stores the records of multiple users and saves the records to the file once.
+func (h *FileHistory) PutAll(recs []UserRecord) error {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	for _, rec := range recs {
+		h.records[strings.ToLower(rec.Name)] = rec
+	}
+	return h.save()
+}
+
+// Records iterates over all stored records.
+func (h *FileHistory) Records(yield func(rec UserRecord) bool) {
+	h.mtx.RLock()
+	for _, rec := range h.records {
+		h.mtx.RUnlock()
+		if !yield(rec) {
+			return
+		}
+		h.mtx.RLock()
+	}
+	h.mtx.RUnlock()
+}
+
+// save writes the records to a temporary file and renames it over the history file.
+// The lock must be held.
+func (h *FileHistory) save() error {
+	records := make([]UserRecord, 0, len(h.records))
+	for _, rec := range h.records {
+		records = append(records, rec)
+	}
+	slices.SortFunc(records, func(a, b UserRecord) int { return strings.Compare(a.Name, b.Name) })
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(h.path), filepath.Base(h.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), h.path)
+}
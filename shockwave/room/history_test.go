@@ -0,0 +1,187 @@
+package room
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	g "xabbo.b7c.io/goearth"
+)
+
+func TestEntityIndex(t *testing.T) {
+	mgr := FromSnapshot(testSnapshot())
+
+	mgr.addEntities([]Entity{
+		{EntityBase: EntityBase{Index: 1, Name: "Alice", Figure: "hd-180-1", Type: User}},
+		{EntityBase: EntityBase{Index: 2, Name: "Bob", Figure: "hd-180-1", Type: User}},
+	})
+
+	if ent := mgr.EntityByName("alice"); ent == nil || ent.Index != 1 {
+		t.Fatalf("incorrect entity by name, expected index: 1, actual: %v", ent)
+	}
+	if ents := mgr.EntitiesByFigure("hd-180-1"); len(ents) != 2 {
+		t.Fatalf("incorrect entity count by figure, expected: 2, actual: %d", len(ents))
+	}
+
+	mgr.removeEntity(1)
+	if ent := mgr.EntityByName("Alice"); ent != nil {
+		t.Fatalf("expected removed entity not to be found, actual: %v", ent)
+	}
+	if ents := mgr.EntitiesByFigure("hd-180-1"); len(ents) != 1 || ents[0].Name != "Bob" {
+		t.Fatalf("incorrect entities by figure: %v", ents)
+	}
+
+	// the server re-sends the user when their figure changes
+	mgr.addEntities([]Entity{{EntityBase: EntityBase{Index: 2, Name: "Bob", Figure: "hd-190-1", Type: User}}})
+	if ents := mgr.EntitiesByFigure("hd-180-1"); len(ents) != 0 {
+		t.Fatalf("expected no entities with the previous figure, actual: %v", ents)
+	}
+	if ents := mgr.EntitiesByFigure("hd-190-1"); len(ents) != 1 || ents[0].Name != "Bob" {
+		t.Fatalf("incorrect entities by figure: %v", ents)
+	}
+}
+
+// countingHistory is a history store that counts the number of writes.
+type countingHistory struct {
+	records map[string]UserRecord
+	writes  int
+}
+
+func (h *countingHistory) Get(name string) (UserRecord, bool, error) {
+	rec, ok := h.records[strings.ToLower(name)]
+	return rec, ok, nil
+}
+
+func (h *countingHistory) Put(rec UserRecord) error {
+	return h.PutAll([]UserRecord{rec})
+}
+
+func (h *countingHistory) PutAll(recs []UserRecord) error {
+	h.writes++
+	for _, rec := range recs {
+		h.records[strings.ToLower(rec.Name)] = rec
+	}
+	return nil
+}
+
+func TestHistoryBatch(t *testing.T) {
+	store := &countingHistory{records: map[string]UserRecord{}}
+	mgr := FromSnapshot(testSnapshot())
+	mgr.SetHistoryStore(store)
+
+	mgr.recordHistory(123, []Entity{
+		{EntityBase: EntityBase{Index: 1, Name: "Alice", Type: User}},
+		{EntityBase: EntityBase{Index: 2, Name: "Bob", Type: User}},
+		{EntityBase: EntityBase{Index: 3, Name: "Carol", Type: User}},
+	})
+	mgr.FlushHistory()
+	if store.writes != 1 || len(store.records) != 3 {
+		t.Fatalf("incorrect history writes, expected: 1 write of 3 records, actual: %d writes of %d records",
+			store.writes, len(store.records))
+	}
+}
+
+// blockingHistory is a history store that blocks writes until it is released.
+type blockingHistory struct {
+	countingHistory
+	release chan struct{}
+}
+
+func (h *blockingHistory) PutAll(recs []UserRecord) error {
+	<-h.release
+	return h.countingHistory.PutAll(recs)
+}
+
+func TestHistoryBackground(t *testing.T) {
+	store := &blockingHistory{
+		countingHistory: countingHistory{records: map[string]UserRecord{}},
+		release:         make(chan struct{}),
+	}
+	mgr := FromSnapshot(testSnapshot())
+	mgr.SetHistoryStore(store)
+
+	done := make(chan struct{})
+	go func() {
+		mgr.recordHistory(123, []Entity{{EntityBase: EntityBase{Name: "Alice", Type: User}}})
+		mgr.recordHistory(123, []Entity{{EntityBase: EntityBase{Name: "Bob", Type: User}}})
+		mgr.recordHistory(123, []Entity{{EntityBase: EntityBase{Name: "Alice", Figure: "hd-190-1", Type: User}}})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("recording history blocked on the store")
+	}
+
+	close(store.release)
+	mgr.FlushHistory()
+	if len(store.records) != 2 || store.records["alice"].Figure != "hd-190-1" {
+		t.Fatalf("incorrect records: %+v", store.records)
+	}
+	if store.writes > 2 {
+		t.Fatalf("incorrect history writes, expected at most: 2, actual: %d", store.writes)
+	}
+}
+
+func TestHistoryLastSeenOnLeave(t *testing.T) {
+	store := &countingHistory{records: map[string]UserRecord{}}
+	mgr := FromSnapshot(testSnapshot())
+	mgr.SetHistoryStore(store)
+
+	seen := time.Now().Add(-time.Hour)
+	store.records["user"] = UserRecord{Name: "user", RoomId: 123, FirstSeen: seen, LastSeen: seen}
+
+	pkt := &g.Packet{Client: g.Shockwave, Header: g.Header{Dir: g.In}}
+	pkt.Write("0")
+	pkt.Pos = 0
+	mgr.handleLogout(g.NewIntercept(nil, pkt, 0, false))
+	mgr.FlushHistory()
+
+	rec := store.records["user"]
+	if !rec.LastSeen.After(seen) {
+		t.Fatalf("expected last seen to be updated, previous: %v, actual: %v", seen, rec.LastSeen)
+	}
+	if !rec.FirstSeen.Equal(seen) {
+		t.Fatalf("incorrect first seen, expected: %v, actual: %v", seen, rec.FirstSeen)
+	}
+}
+
+func TestFileHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	store, err := NewFileHistory(path)
+	if err != nil {
+		t.Fatalf("failed to create history: %v", err)
+	}
+
+	mgr := FromSnapshot(testSnapshot())
+	mgr.SetHistoryStore(store)
+	mgr.recordHistory(123, []Entity{
+		{EntityBase: EntityBase{Name: "Alice", Figure: "hd-180-1", Custom: "hello", Type: User}},
+		{EntityBase: EntityBase{Name: "Pet", Type: Pet}},
+	})
+	mgr.FlushHistory()
+
+	first, ok, _ := store.Get("alice")
+	if !ok || first.RoomId != 123 || first.Custom != "hello" {
+		t.Fatalf("incorrect record: %+v", first)
+	}
+	if _, ok, _ := store.Get("pet"); ok {
+		t.Fatalf("expected pets not to be recorded")
+	}
+
+	mgr.recordHistory(123, []Entity{{EntityBase: EntityBase{Name: "Alice", Figure: "hd-190-1", Type: User}}})
+	mgr.FlushHistory()
+
+	reloaded, err := NewFileHistory(path)
+	if err != nil {
+		t.Fatalf("failed to reload history: %v", err)
+	}
+	rec, ok, _ := reloaded.Get("Alice")
+	if !ok || rec.Figure != "hd-190-1" {
+		t.Fatalf("incorrect reloaded record: %+v", rec)
+	}
+	if !rec.FirstSeen.Equal(first.FirstSeen) {
+		t.Fatalf("incorrect first seen, expected: %v, actual: %v", first.FirstSeen, rec.FirstSeen)
+	}
+}
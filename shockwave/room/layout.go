@@ -0,0 +1,233 @@
+package room
+
+import (
+	"cmp"
+	"context"
+	"math"
+	"slices"
+	"time"
+
+	g "xabbo.b7c.io/goearth"
+	"xabbo.b7c.io/goearth/shockwave/in"
+	"xabbo.b7c.io/goearth/shockwave/inventory"
+)
+
+const (
+	// DefaultRestoreInterval is the default delay between each action when restoring a layout.
+	DefaultRestoreInterval = 500 * time.Millisecond
+	// DefaultRestoreTimeout is the default time to wait for the server to confirm each action when restoring a layout.
+	DefaultRestoreTimeout = 5 * time.Second
+)
+
+// LayoutObject represents the saved placement of a floor item.
+type LayoutObject struct {
+	Class     string  `json:"class"`
+	X         int     `json:"x"`
+	Y         int     `json:"y"`
+	Z         float64 `json:"z"`
+	Direction int     `json:"dir"`
+}
+
+// Layout represents a saved arrangement of the floor items in a room.
+type Layout struct {
+	RoomId  int            `json:"roomId"`
+	Model   string         `json:"model"`
+	Objects []LayoutObject `json:"objects"`
+}
+
+// SaveLayout records the class, position, direction and stack height of each floor item in the current room.
+func (mgr *Manager) SaveLayout() *Layout {
+	snapshot := mgr.Snapshot()
+	layout := &Layout{
+		RoomId:  snapshot.Id,
+		Model:   snapshot.Model,
+		Objects: make([]LayoutObject, 0, len(snapshot.Objects)),
+	}
+	for _, obj := range snapshot.Objects {
+		layout.Objects = append(layout.Objects, LayoutObject{
+			Class:     obj.Class,
+			X:         obj.X,
+			Y:         obj.Y,
+			Z:         obj.Z,
+			Direction: obj.Direction,
+		})
+	}
+	return layout
+}
+
+// RestoreOptions holds the options used when restoring a layout.
+type RestoreOptions struct {
+	// Interval is the delay between each action. Defaults to DefaultRestoreInterval.
+	Interval time.Duration
+	// Timeout is the time to wait for the server to confirm each action. Defaults to DefaultRestoreTimeout.
+	Timeout time.Duration
+	// Inventory is used to place items that are not in the room. Optional.
+	Inventory *inventory.Manager
+}
+
+// RestoreResult holds the result of restoring a layout.
+type RestoreResult struct {
+	// Unchanged holds the objects that were already in place.
+	Unchanged []LayoutObject
+	// Moved holds the objects that were moved from elsewhere in the room, as confirmed by the server.
+	Moved []LayoutObject
+	// Placed holds the objects that were placed from the inventory, as confirmed by the server.
+	Placed []LayoutObject
+	// Misplaced holds the objects that were moved or placed at the saved position,
+	// but ended up at a different stack height, e.g. because an item below them is missing.
+	// These objects are also included in Moved or Placed.
+	Misplaced []LayoutObject
+	// Failed holds the objects that the server did not move or place as requested.
+	Failed []LayoutObject
+	// Missing holds the objects for which no matching item was found in the room or inventory.
+	Missing []LayoutObject
+}
+
+// RestoreLayout restores the specified layout in the current room.
+// Each saved object is matched by class to a floor item in the room, which is moved into place,
+// or to an item in the inventory, which is placed into the room.
+// The stack height of an object cannot be set directly, as the server stacks items on top of each other,
+// so objects are restored from the lowest stack height upwards, with a delay between each action.
+// Each action waits for the server to confirm the object's new position before continuing.
+// Objects that could not be matched are reported as missing.
+// If the context is cancelled, the partial result is returned along with the context's error.
+func (mgr *Manager) RestoreLayout(ctx context.Context, layout *Layout, opts RestoreOptions) (*RestoreResult, error) {
+	if err := mgr.requireRights(); err != nil {
+		return nil, err
+	}
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = DefaultRestoreInterval
+	}
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultRestoreTimeout
+	}
+
+	result := &RestoreResult{}
+
+	entries := slices.Clone(layout.Objects)
+	slices.SortStableFunc(entries, func(a, b LayoutObject) int { return cmp.Compare(a.Z, b.Z) })
+
+	// Available floor items in the room by class, ordered by ID.
+	available := map[string][]Object{}
+	for _, obj := range mgr.Snapshot().Objects {
+		available[obj.Class] = append(available[obj.Class], obj)
+	}
+
+	// Keep objects that are already in place.
+	pending := entries[:0]
+	for _, entry := range entries {
+		objs := available[entry.Class]
+		i := slices.IndexFunc(objs, func(obj Object) bool {
+			return obj.X == entry.X && obj.Y == entry.Y && obj.Direction == entry.Direction
+		})
+		if i >= 0 {
+			available[entry.Class] = slices.Delete(objs, i, i+1)
+			result.Unchanged = append(result.Unchanged, entry)
+		} else {
+			pending = append(pending, entry)
+		}
+	}
+
+	var updates chan Object
+	usedItems := map[int]bool{}
+	first := true
+	for _, entry := range pending {
+		var id int
+		var action func()
+		var placed bool
+		if objs := available[entry.Class]; len(objs) > 0 {
+			obj := objs[0]
+			available[entry.Class] = objs[1:]
+			id = obj.Id
+			action = func() { mgr.MoveObject(obj.Id, entry.X, entry.Y, entry.Direction) }
+		} else if item, ok := findInventoryItem(opts.Inventory, entry.Class, usedItems); ok {
+			usedItems[item.ItemId] = true
+			id, placed = item.Id, true
+			action = func() { mgr.PlaceObject(item, entry.X, entry.Y, entry.Direction) }
+		} else {
+			result.Missing = append(result.Missing, entry)
+			continue
+		}
+
+		if first {
+			if err := ctx.Err(); err != nil {
+				return result, err
+			}
+			// Receive the objects updated or added by the server to confirm each action.
+			updates = make(chan Object, 64)
+			ref := mgr.ix.Intercept(in.ACTIVEOBJECT_UPDATE, in.ACTIVEOBJECT_ADD).Transient().With(func(e *g.Intercept) {
+				var obj Object
+				e.Packet.Read(&obj)
+				select {
+				case updates <- obj:
+				default:
+					dbg.Printf("WARNING: dropped object update while restoring layout (ID: %d)", obj.Id)
+				}
+			})
+			defer ref.Deregister()
+		} else {
+			select {
+			case <-time.After(interval):
+			case <-ctx.Done():
+				return result, ctx.Err()
+			}
+		}
+		first = false
+
+		action()
+		obj, ok, err := awaitObject(ctx, updates, id, timeout)
+		switch {
+		case err != nil:
+			return result, err
+		case !ok || obj.X != entry.X || obj.Y != entry.Y || obj.Direction != entry.Direction:
+			result.Failed = append(result.Failed, entry)
+			continue
+		case placed:
+			result.Placed = append(result.Placed, entry)
+		default:
+			result.Moved = append(result.Moved, entry)
+		}
+		if math.Abs(obj.Z-entry.Z) > 0.01 {
+			result.Misplaced = append(result.Misplaced, entry)
+		}
+	}
+
+	dbg.Printf("restored layout: %d unchanged, %d moved, %d placed, %d failed, %d missing",
+		len(result.Unchanged), len(result.Moved), len(result.Placed), len(result.Failed), len(result.Missing))
+	return result, nil
+}
+
+// awaitObject waits for the server to update or add the object with the specified ID.
+// Returns ok = false if no update was received before the timeout,
+// or the context's error if it is done.
+func awaitObject(ctx context.Context, updates <-chan Object, id int, timeout time.Duration) (obj Object, ok bool, err error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		select {
+		case obj = <-updates:
+			if obj.Id == id {
+				return obj, true, nil
+			}
+		case <-timer.C:
+			dbg.Printf("WARNING: timed out waiting for object update (ID: %d)", id)
+			return Object{}, false, nil
+		case <-ctx.Done():
+			return Object{}, false, ctx.Err()
+		}
+	}
+}
+
+// findInventoryItem finds an unused floor item of the specified class in the inventory.
+func findInventoryItem(inv *inventory.Manager, class string, used map[int]bool) (inventory.Item, bool) {
+	if inv != nil {
+		for item := range inv.Items {
+			if item.Type == inventory.Floor && item.Class == class && !used[item.ItemId] {
+				return item, true
+			}
+		}
+	}
+	return inventory.Item{}, false
+}
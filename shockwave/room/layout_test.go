@@ -0,0 +1,142 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	g "xabbo.b7c.io/goearth"
+	"xabbo.b7c.io/goearth/internal/testix"
+	"xabbo.b7c.io/goearth/shockwave/in"
+	"xabbo.b7c.io/goearth/shockwave/inventory"
+	"xabbo.b7c.io/goearth/shockwave/out"
+)
+
+func TestSaveLayout(t *testing.T) {
+	mgr := FromSnapshot(testSnapshot())
+
+	layout := mgr.SaveLayout()
+	expected := []LayoutObject{
+		{Class: "chair", X: 1, Y: 1, Direction: 2},
+		{Class: "table", X: 2, Y: 1},
+	}
+	if layout.RoomId != 123 || !reflect.DeepEqual(layout.Objects, expected) {
+		t.Fatalf("incorrect layout, expected: %+v, actual: %+v", expected, layout.Objects)
+	}
+}
+
+func TestRestoreLayout(t *testing.T) {
+	mgr := FromSnapshot(testSnapshot())
+	layout := mgr.SaveLayout()
+	layout.Objects = append(layout.Objects, LayoutObject{Class: "plant", X: 3, Y: 2})
+
+	if _, err := mgr.RestoreLayout(context.Background(), layout, RestoreOptions{}); !errors.Is(err, ErrNoRights) {
+		t.Fatalf("incorrect error, expected: %v, actual: %v", ErrNoRights, err)
+	}
+
+	mgr.hasRights = true
+	result, err := mgr.RestoreLayout(context.Background(), layout, RestoreOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Unchanged) != 2 || len(result.Moved) != 0 || len(result.Placed) != 0 {
+		t.Fatalf("incorrect result: %+v", result)
+	}
+	if len(result.Missing) != 1 || result.Missing[0].Class != "plant" {
+		t.Fatalf("incorrect missing objects: %+v", result.Missing)
+	}
+}
+
+// objectValues returns the packet values of a floor item.
+func objectValues(obj Object) []any {
+	return []any{strconv.Itoa(obj.Id), obj.Class, obj.X, obj.Y, obj.Width, obj.Height,
+		obj.Direction, strconv.FormatFloat(obj.Z, 'f', 1, 64), "", "", 0, ""}
+}
+
+// packetFields returns a function that parses the space-separated integer fields of a packet.
+func packetFields(pkt *g.Packet) func(i int) int {
+	fields := strings.Fields(string(pkt.Data))
+	return func(i int) int { v, _ := strconv.Atoi(fields[i]); return v }
+}
+
+// newRestoreTest creates a room manager whose server confirms MOVESTUFF and PLACESTUFF requests,
+// unless reject is true, in which case moved objects are sent back at their original position.
+func newRestoreTest(reject bool) (*testix.Interceptor, *Manager, *inventory.Manager) {
+	ix := testix.New(g.Shockwave)
+	ix.Resolve(in.ACTIVEOBJECT_UPDATE, in.ACTIVEOBJECT_ADD)
+	mgr := NewManager(ix)
+	inv := inventory.NewManager(ix)
+	ix.Respond(out.MOVESTUFF, func(ix *testix.Interceptor, pkt *g.Packet) {
+		n := packetFields(pkt)
+		obj := *mgr.Object(n(0))
+		if !reject {
+			obj.X, obj.Y, obj.Direction = n(1), n(2), n(3)
+		}
+		ix.Dispatch(in.ACTIVEOBJECT_UPDATE, objectValues(obj)...)
+	})
+	ix.Respond(out.PLACESTUFF, func(ix *testix.Interceptor, pkt *g.Packet) {
+		n := packetFields(pkt)
+		// inventory item 5 is furni 500
+		obj := Object{Id: 500, Class: "plant", X: n(1), Y: n(2), Width: 1, Height: 1, Direction: n(5), Z: 1}
+		ix.Dispatch(in.ACTIVEOBJECT_ADD, objectValues(obj)...)
+	})
+	mgr.LoadSnapshot(testSnapshot())
+	mgr.hasRights = true
+	ix.Dispatch(in.STRIPINFO_2, g.Length(1), 5, 0, "S", 500, "plant", 1, 1, "")
+	return ix, mgr, inv
+}
+
+func TestRestoreLayoutActions(t *testing.T) {
+	ix, mgr, inv := newRestoreTest(false)
+	defer ix.Close()
+
+	layout := &Layout{Objects: []LayoutObject{
+		{Class: "chair", X: 0, Y: 2, Direction: 4},
+		{Class: "table", X: 2, Y: 1},
+		{Class: "plant", X: 3, Y: 2, Z: 1},
+		{Class: "lamp", X: 1, Y: 0},
+	}}
+	opts := RestoreOptions{Interval: time.Millisecond, Timeout: time.Second, Inventory: inv}
+	result, err := mgr.RestoreLayout(context.Background(), layout, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	counts := []int{len(result.Unchanged), len(result.Moved), len(result.Placed),
+		len(result.Misplaced), len(result.Failed), len(result.Missing)}
+	if expected := []int{1, 1, 1, 0, 0, 1}; !reflect.DeepEqual(counts, expected) {
+		t.Fatalf("incorrect result counts (unchanged, moved, placed, misplaced, failed, missing), expected: %v, actual: %v",
+			expected, counts)
+	}
+
+	moves := ix.SentTo(out.MOVESTUFF)
+	if len(moves) != 1 || string(moves[0].Data) != "1 0 2 4" {
+		t.Fatalf("incorrect MOVESTUFF packets: %v", moves)
+	}
+	places := ix.SentTo(out.PLACESTUFF)
+	if len(places) != 1 || string(places[0].Data) != "5 3 2 1 1 0" {
+		t.Fatalf("incorrect PLACESTUFF packets: %v", places)
+	}
+	if obj := mgr.Object(1); obj == nil || obj.X != 0 || obj.Y != 2 {
+		t.Fatalf("incorrect moved object: %v", obj)
+	}
+}
+
+func TestRestoreLayoutRejected(t *testing.T) {
+	ix, mgr, _ := newRestoreTest(true)
+	defer ix.Close()
+
+	layout := &Layout{Objects: []LayoutObject{{Class: "chair", X: 0, Y: 2, Direction: 4}}}
+	opts := RestoreOptions{Interval: time.Millisecond, Timeout: time.Second}
+	result, err := mgr.RestoreLayout(context.Background(), layout, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Moved) != 0 || len(result.Failed) != 1 {
+		t.Fatalf("incorrect result, expected 1 failed object, actual: %+v", result)
+	}
+}
@@ -0,0 +1,316 @@
+package room
+
+import (
+	"errors"
+	"hash/fnv"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+)
+
+// ErrNoHeightmap is returned when rendering a room whose heightmap has not been received.
+var ErrNoHeightmap = errors.New("heightmap not received")
+
+// DefaultTileSize is the default size of a tile in pixels when rendering a room image.
+const DefaultTileSize = 24
+
+// RenderOptions holds the options used when rendering a room.
+type RenderOptions struct {
+	// LabelObjects labels floor items with their class.
+	LabelObjects bool
+	// LabelEntities labels entities with their name.
+	LabelEntities bool
+	// ShowHeights shows the height of each floor tile in the ASCII floor plan.
+	ShowHeights bool
+	// TileSize is the size of a tile in pixels when rendering an image. Defaults to DefaultTileSize.
+	TileSize int
+}
+
+// ASCII floor plan symbols.
+const (
+	asciiVoid    = ' '
+	asciiFloor   = '.'
+	asciiObject  = '#'
+	asciiPassive = '+'
+	asciiEntity  = '@'
+)
+
+// RenderASCII renders the room as an ASCII floor plan, with one character per tile.
+// Blocked tiles are shown as spaces, floor tiles as '.', floor items as '#', passive objects as '+' and entities as '@'.
+// When labels are enabled, floor items are lettered A-Z by class and entities a-z,
+// and a legend is appended below the floor plan.
+// Returns an empty string if the heightmap has not been received.
+func (snapshot *Snapshot) RenderASCII(opts RenderOptions) string {
+	hm := snapshot.Heightmap
+	if hm == nil {
+		return ""
+	}
+
+	grid := make([][]byte, hm.Length)
+	heights := strings.Split(hm.String(), "\r")
+	for y := range grid {
+		grid[y] = make([]byte, hm.Width)
+		for x := range grid[y] {
+			switch {
+			case hm.At(x, y).Blocked:
+				grid[y][x] = asciiVoid
+			case opts.ShowHeights:
+				grid[y][x] = heights[y][x]
+			default:
+				grid[y][x] = asciiFloor
+			}
+		}
+	}
+	set := func(x, y int, c byte) {
+		if hm.InBounds(x, y) {
+			grid[y][x] = c
+		}
+	}
+
+	var legend []string
+
+	for _, obj := range snapshot.PassiveObjects {
+		set(obj.X, obj.Y, asciiPassive)
+	}
+
+	classSymbols := map[string]byte{}
+	for _, obj := range snapshot.Objects {
+		c := byte(asciiObject)
+		if opts.LabelObjects {
+			var ok bool
+			if c, ok = classSymbols[obj.Class]; !ok {
+				c = labelSymbol('A', len(classSymbols))
+				classSymbols[obj.Class] = c
+				legend = append(legend, string(c)+": "+obj.Class)
+			}
+		}
+		for _, pt := range obj.Footprint() {
+			set(pt.X, pt.Y, c)
+		}
+	}
+
+	for i, ent := range snapshot.Entities {
+		c := byte(asciiEntity)
+		if opts.LabelEntities {
+			c = labelSymbol('a', i)
+			legend = append(legend, string(c)+": "+ent.Name+" ("+ent.Type.String()+")")
+		}
+		set(ent.X, ent.Y, c)
+	}
+
+	var sb strings.Builder
+	for _, line := range grid {
+		sb.WriteString(strings.TrimRight(string(line), " "))
+		sb.WriteByte('\n')
+	}
+	if len(legend) > 0 {
+		sb.WriteByte('\n')
+		for _, line := range legend {
+			sb.WriteString(line)
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String()
+}
+
+// labelSymbol returns the i-th letter starting from base, or '?' if there are no letters left.
+func labelSymbol(base byte, i int) byte {
+	if i >= 26 {
+		return '?'
+	}
+	return base + byte(i)
+}
+
+// Render colors.
+var (
+	colorVoid    = color.RGBA{0x20, 0x20, 0x20, 0xff}
+	colorGrid    = color.RGBA{0x50, 0x50, 0x50, 0xff}
+	colorPassive = color.RGBA{0x6b, 0x4f, 0x2f, 0xff}
+	colorUser    = color.RGBA{0xe0, 0x40, 0x40, 0xff}
+	colorPet     = color.RGBA{0x40, 0xc0, 0x40, 0xff}
+	colorBot     = color.RGBA{0x40, 0x80, 0xe0, 0xff}
+	colorLabel   = color.RGBA{0xff, 0xff, 0xff, 0xff}
+)
+
+// RenderImage renders a top-down image of the room.
+// Floor tiles are shaded by height, floor items are colored by class,
+// and entities are drawn as squares colored by their type.
+// Labels are drawn in upper case with a small built-in font.
+// Returns nil if the heightmap has not been received.
+func (snapshot *Snapshot) RenderImage(opts RenderOptions) *image.RGBA {
+	hm := snapshot.Heightmap
+	if hm == nil {
+		return nil
+	}
+	size := opts.TileSize
+	if size <= 0 {
+		size = DefaultTileSize
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, hm.Width*size, hm.Length*size))
+	fill(img, img.Bounds(), colorVoid)
+
+	tileRect := func(x, y int) image.Rectangle {
+		return image.Rect(x*size, y*size, (x+1)*size, (y+1)*size)
+	}
+
+	for y := range hm.Length {
+		for x := range hm.Width {
+			tile := hm.At(x, y)
+			if tile.Blocked {
+				continue
+			}
+			shade := uint8(min(0x90+int(tile.Height)*8, 0xf0))
+			r := tileRect(x, y)
+			fill(img, r, colorGrid)
+			fill(img, r.Inset(1), color.RGBA{shade, shade, shade, 0xff})
+		}
+	}
+
+	for _, obj := range snapshot.PassiveObjects {
+		fill(img, tileRect(obj.X, obj.Y).Inset(2), colorPassive)
+	}
+
+	for _, obj := range snapshot.Objects {
+		c := classColor(obj.Class)
+		for _, pt := range obj.Footprint() {
+			fill(img, tileRect(pt.X, pt.Y).Inset(2), c)
+		}
+	}
+
+	for _, ent := range snapshot.Entities {
+		c := colorUser
+		switch ent.Type {
+		case Pet:
+			c = colorPet
+		case PublicBot, PrivateBot:
+			c = colorBot
+		}
+		fill(img, tileRect(ent.X, ent.Y).Inset(size/4), c)
+	}
+
+	if opts.LabelObjects {
+		for _, obj := range snapshot.Objects {
+			r := tileRect(obj.X, obj.Y)
+			drawText(img, r.Min.X+2, r.Min.Y+2, obj.Class, colorLabel)
+		}
+	}
+	if opts.LabelEntities {
+		for _, ent := range snapshot.Entities {
+			r := tileRect(ent.X, ent.Y)
+			drawText(img, r.Min.X+2, r.Max.Y-glyphHeight-2, ent.Name, colorLabel)
+		}
+	}
+
+	return img
+}
+
+// EncodePNG renders a top-down image of the room and encodes it to w as a PNG.
+func (snapshot *Snapshot) EncodePNG(w io.Writer, opts RenderOptions) error {
+	img := snapshot.RenderImage(opts)
+	if img == nil {
+		return ErrNoHeightmap
+	}
+	return png.Encode(w, img)
+}
+
+// RenderASCII renders the current room as an ASCII floor plan.
+// See Snapshot.RenderASCII.
+func (mgr *Manager) RenderASCII(opts RenderOptions) string {
+	return mgr.Snapshot().RenderASCII(opts)
+}
+
+// RenderImage renders a top-down image of the current room.
+// See Snapshot.RenderImage.
+func (mgr *Manager) RenderImage(opts RenderOptions) *image.RGBA {
+	return mgr.Snapshot().RenderImage(opts)
+}
+
+// EncodePNG renders a top-down image of the current room and encodes it to w as a PNG.
+func (mgr *Manager) EncodePNG(w io.Writer, opts RenderOptions) error {
+	return mgr.Snapshot().EncodePNG(w, opts)
+}
+
+func fill(img *image.RGBA, r image.Rectangle, c color.RGBA) {
+	r = r.Intersect(img.Bounds())
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+}
+
+// classColor returns a color derived from the hash of a class name,
+// so that items of the same class share a color.
+func classColor(class string) color.RGBA {
+	h := fnv.New32a()
+	h.Write([]byte(class))
+	sum := h.Sum32()
+	return color.RGBA{0x40 + uint8(sum)%0xa0, 0x40 + uint8(sum>>8)%0xa0, 0x40 + uint8(sum>>16)%0xa0, 0xff}
+}
+
+const (
+	glyphWidth  = 3
+	glyphHeight = 5
+)
+
+// drawText draws text at the specified position using a 3x5 pixel font.
+// Characters without a glyph are drawn as spaces.
+func drawText(img *image.RGBA, x, y int, text string, c color.RGBA) {
+	for _, r := range strings.ToUpper(text) {
+		if glyph, ok := glyphs[r]; ok {
+			for gy, row := range glyph {
+				for gx := range glyphWidth {
+					if row[gx] == '#' && (image.Point{x + gx, y + gy}).In(img.Bounds()) {
+						img.SetRGBA(x+gx, y+gy, c)
+					}
+				}
+			}
+		}
+		x += glyphWidth + 1
+	}
+}
+
+var glyphs = map[rune][glyphHeight]string{
+	'A': {".#.", "#.#", "###", "#.#", "#.#"},
+	'B': {"##.", "#.#", "##.", "#.#", "##."},
+	'C': {".##", "#..", "#..", "#..", ".##"},
+	'D': {"##.", "#.#", "#.#", "#.#", "##."},
+	'E': {"###", "#..", "##.", "#..", "###"},
+	'F': {"###", "#..", "##.", "#..", "#.."},
+	'G': {".##", "#..", "#.#", "#.#", ".##"},
+	'H': {"#.#", "#.#", "###", "#.#", "#.#"},
+	'I': {"###", ".#.", ".#.", ".#.", "###"},
+	'J': {"..#", "..#", "..#", "#.#", ".#."},
+	'K': {"#.#", "#.#", "##.", "#.#", "#.#"},
+	'L': {"#..", "#..", "#..", "#..", "###"},
+	'M': {"#.#", "###", "###", "#.#", "#.#"},
+	'N': {"##.", "#.#", "#.#", "#.#", "#.#"},
+	'O': {".#.", "#.#", "#.#", "#.#", ".#."},
+	'P': {"##.", "#.#", "##.", "#..", "#.."},
+	'Q': {".#.", "#.#", "#.#", "##.", ".##"},
+	'R': {"##.", "#.#", "##.", "#.#", "#.#"},
+	'S': {".##", "#..", ".#.", "..#", "##."},
+	'T': {"###", ".#.", ".#.", ".#.", ".#."},
+	'U': {"#.#", "#.#", "#.#", "#.#", "###"},
+	'V': {"#.#", "#.#", "#.#", "#.#", ".#."},
+	'W': {"#.#", "#.#", "###", "###", "#.#"},
+	'X': {"#.#", "#.#", ".#.", "#.#", "#.#"},
+	'Y': {"#.#", "#.#", ".#.", ".#.", ".#."},
+	'Z': {"###", "..#", ".#.", "#..", "###"},
+	'0': {"###", "#.#", "#.#", "#.#", "###"},
+	'1': {".#.", "##.", ".#.", ".#.", "###"},
+	'2': {"##.", "..#", ".#.", "#..", "###"},
+	'3': {"##.", "..#", ".#.", "..#", "##."},
+	'4': {"#.#", "#.#", "###", "..#", "..#"},
+	'5': {"###", "#..", "##.", "..#", "##."},
+	'6': {".##", "#..", "###", "#.#", "###"},
+	'7': {"###", "..#", ".#.", ".#.", ".#."},
+	'8': {"###", "#.#", "###", "#.#", "###"},
+	'9': {"###", "#.#", "###", "..#", "##."},
+	'_': {"...", "...", "...", "...", "###"},
+	'-': {"...", "...", "###", "...", "..."},
+	'.': {"...", "...", "...", "...", ".#."},
+}
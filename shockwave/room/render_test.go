@@ -0,0 +1,50 @@
+package room
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+)
+
+func TestRenderASCII(t *testing.T) {
+	snapshot := testSnapshot()
+
+	expected := "  ..\n" +
+		".###\n" +
+		".@##\n"
+	if actual := snapshot.RenderASCII(RenderOptions{}); actual != expected {
+		t.Fatalf("incorrect floor plan, expected:\n%s\nactual:\n%s", expected, actual)
+	}
+
+	expected = "  00\n" +
+		"0ABB\n" +
+		"0aBB\n" +
+		"\n" +
+		"A: chair\n" +
+		"B: table\n" +
+		"a: user (user)\n"
+	opts := RenderOptions{LabelObjects: true, LabelEntities: true, ShowHeights: true}
+	if actual := snapshot.RenderASCII(opts); actual != expected {
+		t.Fatalf("incorrect labelled floor plan, expected:\n%s\nactual:\n%s", expected, actual)
+	}
+}
+
+func TestEncodePNG(t *testing.T) {
+	snapshot := testSnapshot()
+
+	var buf bytes.Buffer
+	if err := snapshot.EncodePNG(&buf, RenderOptions{LabelObjects: true, LabelEntities: true, TileSize: 10}); err != nil {
+		t.Fatalf("failed to encode image: %v", err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("failed to decode image: %v", err)
+	}
+	if size := img.Bounds().Size(); size.X != 40 || size.Y != 30 {
+		t.Fatalf("incorrect image size, expected: 40x30, actual: %dx%d", size.X, size.Y)
+	}
+
+	if err := (&Snapshot{}).EncodePNG(&buf, RenderOptions{}); err != ErrNoHeightmap {
+		t.Fatalf("incorrect error, expected: %v, actual: %v", ErrNoHeightmap, err)
+	}
+}
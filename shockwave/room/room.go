@@ -57,13 +57,35 @@ func (obj *Object) Parse(p *g.Packet, pos *int) {
 		&obj.Extra, &obj.StuffData)
 }
 
+// PassiveObject represents a static object in a public room, such as a seat or an obstacle.
+type PassiveObject struct {
+	Id        string
+	Class     string
+	X, Y      int
+	Z         float64
+	Direction int
+}
+
+func (obj PassiveObject) String() string {
+	return obj.Class + "(" + obj.Id + ")"
+}
+
+// Tile returns the tile the object is located at.
+func (obj PassiveObject) Tile() Tile {
+	return Tile{obj.X, obj.Y, obj.Z}
+}
+
 // Item represents a wall item in a room.
 type Item struct {
-	Id       int
-	Class    string
-	Owner    string
-	Location string
-	Type     string
+	Id    int
+	Class string
+	Owner string
+	// Location is the parsed wall location, valid only if ValidLocation is true.
+	Location WallLocation
+	// RawLocation is the wall location as sent by the server.
+	RawLocation   string
+	ValidLocation bool
+	Type          string
 }
 
 func (item Item) String() string {
@@ -97,12 +119,19 @@ func (item *Item) ParseString(s string) {
 		panic("failed to parse Item ID: " + fields[0])
 	}
 
+	location, err := ParseWallLocation(fields[3])
+	if err != nil {
+		dbg.Printf("WARNING: failed to parse location of item %d: %s", id, err)
+	}
+
 	*item = Item{
-		Id:       id,
-		Class:    fields[1],
-		Owner:    fields[2],
-		Location: fields[3],
-		Type:     fields[4],
+		Id:            id,
+		Class:         fields[1],
+		Owner:         fields[2],
+		Location:      location,
+		RawLocation:   fields[3],
+		ValidLocation: err == nil,
+		Type:          fields[4],
 	}
 }
 
@@ -221,6 +250,8 @@ type Entity struct {
 	Dir     int
 	HeadDir int
 	Action  string
+	// Statuses holds the statuses parsed from Action.
+	Statuses Statuses
 }
 
 func (ent Entity) String() string {
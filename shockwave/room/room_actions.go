@@ -0,0 +1,186 @@
+package room
+
+import (
+	"errors"
+	"strconv"
+
+	g "xabbo.b7c.io/goearth"
+	"xabbo.b7c.io/goearth/shockwave/inventory"
+	"xabbo.b7c.io/goearth/shockwave/out"
+)
+
+// ErrNotUsable is returned when using a floor item that is neither a dice nor an on/off object.
+var ErrNotUsable = errors.New("object cannot be used")
+
+// Sign represents a sign that can be held up by a user.
+type Sign int
+
+const (
+	Sign0 Sign = iota
+	Sign1
+	Sign2
+	Sign3
+	Sign4
+	Sign5
+	Sign6
+	Sign7
+	Sign8
+	Sign9
+	Sign10
+	SignHeart
+	SignSkull
+	SignExclamation
+	SignSoccerBall
+	SignSmile
+	SignRedCard
+	SignYellowCard
+)
+
+// Dance represents a dance style. DanceNormal is available to all users,
+// other styles require a club subscription.
+type Dance int
+
+const (
+	DanceNormal Dance = iota
+	DancePogoMogo
+	DanceDuckFunk
+	DanceTheRollie
+)
+
+// Walk walks to the specified coordinates.
+func (mgr *Manager) Walk(x, y int) {
+	mgr.ix.Send(out.MOVE, g.B64(x), g.B64(y))
+}
+
+// WalkTo walks to the specified point.
+func (mgr *Manager) WalkTo(pt Point) {
+	mgr.Walk(pt.X, pt.Y)
+}
+
+// Chat sends a chat message.
+func (mgr *Manager) Chat(msg string) {
+	mgr.ix.Send(out.CHAT, msg)
+}
+
+// Shout sends a shout message.
+func (mgr *Manager) Shout(msg string) {
+	mgr.ix.Send(out.SHOUT, msg)
+}
+
+// Whisper sends a whisper message to the user with the specified name.
+func (mgr *Manager) Whisper(recipient, msg string) {
+	mgr.ix.Send(out.WHISPER, recipient+" "+msg)
+}
+
+// Dance starts dancing with the specified dance style.
+func (mgr *Manager) Dance(style Dance) {
+	if style == DanceNormal {
+		mgr.ix.Send(out.DANCE)
+	} else {
+		mgr.ix.Send(out.DANCE, int(style))
+	}
+}
+
+// StopDancing stops dancing.
+func (mgr *Manager) StopDancing() {
+	mgr.Stop("Dance")
+}
+
+// Wave waves.
+func (mgr *Manager) Wave() {
+	mgr.ix.Send(out.WAVE)
+}
+
+// LookTo turns to look at the specified coordinates.
+func (mgr *Manager) LookTo(x, y int) {
+	mgr.ix.Send(out.LOOKTO, []byte(strconv.Itoa(x)+" "+strconv.Itoa(y)))
+}
+
+// Sign holds up the specified sign.
+func (mgr *Manager) Sign(sign Sign) {
+	mgr.ix.Send(out.SIGN, int(sign))
+}
+
+// CarryDrink carries the drink with the specified ID.
+func (mgr *Manager) CarryDrink(drinkId int) {
+	mgr.ix.Send(out.CARRYDRINK, []byte(strconv.Itoa(drinkId)))
+}
+
+// Stop stops the specified action, e.g. "Dance" or "CarryItem".
+func (mgr *Manager) Stop(action string) {
+	mgr.ix.Send(out.STOP, []byte(action))
+}
+
+// PlaceObject places the specified floor item from the inventory at the specified coordinates and direction.
+func (mgr *Manager) PlaceObject(item inventory.Item, x, y, dir int) {
+	mgr.ix.Send(out.PLACESTUFF, []byte(strconv.Itoa(item.ItemId)+
+		" "+strconv.Itoa(x)+" "+strconv.Itoa(y)+
+		" "+strconv.Itoa(item.DimX)+" "+strconv.Itoa(item.DimY)+
+		" "+strconv.Itoa(dir)))
+}
+
+// PlaceItem places the specified wall item from the inventory at the specified wall location.
+func (mgr *Manager) PlaceItem(item inventory.Item, location WallLocation) {
+	mgr.ix.Send(out.PLACESTUFF, []byte(strconv.Itoa(item.ItemId)+" "+location.String()))
+}
+
+// MoveItem moves the wall item with the specified ID to the specified wall location.
+func (mgr *Manager) MoveItem(id int, location WallLocation) {
+	mgr.ix.Send(out.MOVEITEM, []byte(strconv.Itoa(id)+" "+location.String()))
+}
+
+// MoveObject moves the floor item with the specified ID to the specified coordinates and direction.
+func (mgr *Manager) MoveObject(id, x, y, dir int) {
+	mgr.ix.Send(out.MOVESTUFF, []byte(strconv.Itoa(id)+
+		" "+strconv.Itoa(x)+" "+strconv.Itoa(y)+" "+strconv.Itoa(dir)))
+}
+
+// PickUp picks up the floor item with the specified ID.
+func (mgr *Manager) PickUp(id int) {
+	mgr.ix.Send(out.REMOVESTUFF, []byte(strconv.Itoa(id)))
+}
+
+// SetStuffData sets the stuff data of the floor item with the specified ID.
+func (mgr *Manager) SetStuffData(id int, data string) {
+	mgr.ix.Send(out.SETSTUFFDATA, strconv.Itoa(id), data)
+}
+
+// SetState sets the state of the floor item with the specified ID.
+func (mgr *Manager) SetState(id int, state string) {
+	mgr.SetStuffData(id, state)
+}
+
+// SetItemState sets the state of the wall item with the specified ID.
+func (mgr *Manager) SetItemState(id int, state string) {
+	mgr.ix.Send(out.SETITEMSTATE, strconv.Itoa(id), state)
+}
+
+// UseItem uses the wall item with the specified ID.
+func (mgr *Manager) UseItem(id int) {
+	mgr.ix.Send(out.USEITEM, []byte(strconv.Itoa(id)))
+}
+
+// UseObject uses the specified floor item.
+// Dice are thrown and on/off furni such as lamps and gates are toggled.
+// Returns ErrNotUsable for other objects, including teleporters, as floor items have no generic use packet:
+// USEITEM applies to wall items, and other states must be set explicitly with SetState.
+func (mgr *Manager) UseObject(obj Object) error {
+	if obj.IsDice() {
+		mgr.ThrowDice(obj.Id)
+	} else if state, ok := obj.ToggledState(); ok {
+		mgr.SetState(obj.Id, state)
+	} else {
+		return ErrNotUsable
+	}
+	return nil
+}
+
+// ThrowDice throws the dice with the specified ID.
+func (mgr *Manager) ThrowDice(id int) {
+	mgr.ix.Send(out.THROW_DICE, []byte(strconv.Itoa(id)))
+}
+
+// TurnDiceOff turns off the dice with the specified ID.
+func (mgr *Manager) TurnDiceOff(id int) {
+	mgr.ix.Send(out.DICE_OFF, []byte(strconv.Itoa(id)))
+}
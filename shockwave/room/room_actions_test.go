@@ -0,0 +1,101 @@
+package room
+
+import (
+	"errors"
+	"testing"
+
+	g "xabbo.b7c.io/goearth"
+	"xabbo.b7c.io/goearth/internal/testix"
+	"xabbo.b7c.io/goearth/shockwave/inventory"
+	"xabbo.b7c.io/goearth/shockwave/out"
+)
+
+func TestActionEncoding(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       g.Identifier
+		action   func(mgr *Manager)
+		expected string
+	}{
+		{"walk", out.MOVE, func(mgr *Manager) { mgr.Walk(3, 70) }, "@CAF"},
+		{"walk to", out.MOVE, func(mgr *Manager) { mgr.WalkTo(Point{X: 0, Y: 1}) }, "@@@A"},
+		{"look to", out.LOOKTO, func(mgr *Manager) { mgr.LookTo(5, 12) }, "5 12"},
+		{"sign", out.SIGN, func(mgr *Manager) { mgr.Sign(Sign3) }, "K"},
+		{"sign heart", out.SIGN, func(mgr *Manager) { mgr.Sign(SignHeart) }, "SB"},
+		{"dance", out.DANCE, func(mgr *Manager) { mgr.Dance(DanceNormal) }, ""},
+		{"dance style", out.DANCE, func(mgr *Manager) { mgr.Dance(DanceDuckFunk) }, "J"},
+		{"stop dancing", out.STOP, func(mgr *Manager) { mgr.StopDancing() }, "Dance"},
+		{"wave", out.WAVE, func(mgr *Manager) { mgr.Wave() }, ""},
+		{"whisper", out.WHISPER, func(mgr *Manager) { mgr.Whisper("user", "hi") }, "@Guser hi"},
+		{"place object", out.PLACESTUFF, func(mgr *Manager) {
+			mgr.PlaceObject(inventory.Item{ItemId: 1234, DimX: 2, DimY: 1}, 5, 6, 4)
+		}, "1234 5 6 2 1 4"},
+		{"place item", out.PLACESTUFF, func(mgr *Manager) {
+			mgr.PlaceItem(inventory.Item{ItemId: 1234}, WallLocation{WallX: 1, WallY: 2, X: 3, Y: 4, Orientation: WallLeft})
+		}, "1234 :w=1,2 l=3,4 l"},
+		{"move object", out.MOVESTUFF, func(mgr *Manager) { mgr.MoveObject(1234, 5, 6, 2) }, "1234 5 6 2"},
+		{"pick up", out.REMOVESTUFF, func(mgr *Manager) { mgr.PickUp(1234) }, "1234"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ix := testix.New(g.Shockwave)
+			defer ix.Close()
+			test.action(&Manager{ix: ix})
+
+			sent := ix.SentTo(test.id)
+			if len(sent) != 1 {
+				t.Fatalf("incorrect number of packets sent, expected: %d, actual: %d", 1, len(sent))
+			}
+			if actual := string(sent[0].Data); actual != test.expected {
+				t.Fatalf("incorrect packet data, expected: %q, actual: %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestUseObject(t *testing.T) {
+	tests := []struct {
+		name     string
+		obj      Object
+		id       g.Identifier
+		expected string
+	}{
+		{"dice", Object{Id: 1234, Class: "edice", StuffData: "3"}, out.THROW_DICE, "1234"},
+		{"on/off", Object{Id: 1234, Class: "lamp", StuffData: "ON"}, out.SETSTUFFDATA, "@D1234@COFF"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ix := testix.New(g.Shockwave)
+			defer ix.Close()
+			if err := (&Manager{ix: ix}).UseObject(test.obj); err != nil {
+				t.Fatalf("failed to use object: %v", err)
+			}
+
+			sent := ix.Sent()
+			if len(sent) != 1 || !ix.Headers().Is(sent[0].Header, test.id) {
+				t.Fatalf("incorrect packets sent: %v", sent)
+			}
+			if actual := string(sent[0].Data); actual != test.expected {
+				t.Fatalf("incorrect packet data, expected: %q, actual: %q", test.expected, actual)
+			}
+		})
+	}
+
+	for _, obj := range []Object{
+		{Id: 1234, Class: "chair", StuffData: "3"},
+		{Id: 1234, Class: "doorB", StuffData: "TRUE"},
+	} {
+		t.Run(obj.Class, func(t *testing.T) {
+			ix := testix.New(g.Shockwave)
+			defer ix.Close()
+			if err := (&Manager{ix: ix}).UseObject(obj); !errors.Is(err, ErrNotUsable) {
+				t.Fatalf("incorrect error, expected: %v, actual: %v", ErrNotUsable, err)
+			}
+			if sent := ix.Sent(); len(sent) != 0 {
+				t.Fatalf("expected no packets to be sent, actual: %v", sent)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	g "xabbo.b7c.io/goearth"
+	"xabbo.b7c.io/goearth/shockwave/in"
+	"xabbo.b7c.io/goearth/shockwave/out"
+)
+
+var (
+	// ErrWrongPassword is returned when entering a room with an incorrect password.
+	ErrWrongPassword = errors.New("incorrect room password")
+	// ErrBanned is returned when entering a room the user is banned from.
+	ErrBanned = errors.New("banned from room")
+	// ErrRoomFull is returned when entering a room that is full.
+	ErrRoomFull = errors.New("room is full")
+	// ErrDoorbellDenied is returned when the doorbell was not answered or the user was not let in.
+	ErrDoorbellDenied = errors.New("doorbell denied")
+	// ErrCantConnect is returned when the server refuses the connection to the room for another reason.
+	ErrCantConnect = errors.New("cannot connect to room")
+)
+
+// CANTCONNECT reasons.
+const (
+	cantConnectFull   = 1
+	cantConnectBanned = 4
+)
+
+// EnterResult holds the state of a room once it has been entered and loaded.
+type EnterResult struct {
+	Args
+	Objects  []Object
+	Entities []Entity
+}
+
+// Enter enters the room with the specified ID, ringing the doorbell or sending the password if required,
+// and waits until its objects and entities have been loaded.
+// If the door is closed, Enter waits until the doorbell is answered or the context is done.
+// Returns ErrTimeout if the context deadline is exceeded,
+// or one of ErrWrongPassword, ErrBanned, ErrRoomFull, ErrDoorbellDenied or ErrCantConnect if the room could not be entered.
+//
+// Entry consists of TRYFLAT, which is answered once the user is let in or refused,
+// followed by GOTOFLAT, which loads the room.
+// NAVIGATE and GETDOORFLAT are not sent: they request navigator nodes and teleporter destinations,
+// and the server does not require either of them before TRYFLAT.
+// OPC_OK is not awaited, as it is the server's reply to the client's room directory request,
+// which the client sends itself when it changes rooms and which Enter does not send.
+func (mgr *Manager) Enter(ctx context.Context, roomId int, password string) (*EnterResult, error) {
+	letIn := make(chan error, 1)
+	ref := mgr.ix.Intercept(in.FLAT_LETIN, in.FLATPASSWORD_OK, in.DOORBELL_RINGING,
+		in.FLATNOTALLOWEDTOENTER, in.ERROR, in.CANTCONNECT, in.USER_BANNED).
+		Transient().With(func(e *g.Intercept) {
+		if done, err := mgr.letInResult(e); done {
+			select {
+			case letIn <- err:
+			default:
+			}
+		}
+	})
+	mgr.ix.Send(out.TRYFLAT, []byte(strconv.Itoa(roomId)+"/"+password))
+	select {
+	case err := <-letIn:
+		ref.Deregister()
+		if err != nil {
+			return nil, err
+		}
+	case <-ctx.Done():
+		ref.Deregister()
+		return nil, enterCtxErr(ctx)
+	}
+
+	// The manager's own intercepts were registered first and have already
+	// updated its state by the time this handler runs on the same goroutine.
+	var result EnterResult
+	var entered, objectsLoaded, entitiesLoaded, done bool
+	loaded := make(chan struct{})
+	ref = mgr.ix.Intercept(in.ROOM_READY, in.ACTIVEOBJECTS, in.USERS).
+		Transient().With(func(e *g.Intercept) {
+		if done {
+			return
+		}
+		switch {
+		case e.Is(in.ROOM_READY):
+			if mgr.roomId == roomId && !entered {
+				entered = true
+				result.Args = Args{Id: roomId, Info: mgr.roomInfo}
+			}
+		case !entered:
+			return
+		case e.Is(in.ACTIVEOBJECTS):
+			objectsLoaded = true
+			result.Objects = slices.Collect(mgr.Objects)
+		case e.Is(in.USERS):
+			entitiesLoaded = true
+			result.Entities = slices.Collect(mgr.Entities)
+		}
+		if entered && objectsLoaded && entitiesLoaded {
+			done = true
+			close(loaded)
+		}
+	})
+	defer ref.Deregister()
+
+	mgr.ix.Send(out.GOTOFLAT, []byte(strconv.Itoa(roomId)))
+	select {
+	case <-loaded:
+	case <-ctx.Done():
+		return nil, enterCtxErr(ctx)
+	}
+
+	dbg.Printf("entered and loaded room (ID: %d)", roomId)
+	return &result, nil
+}
+
+// letInResult inspects a packet received in response to TRYFLAT.
+// Returns done = true once the user has been let in (err == nil) or entry was refused.
+func (mgr *Manager) letInResult(e *g.Intercept) (done bool, err error) {
+	switch {
+	case e.Is(in.FLAT_LETIN):
+		return true, nil
+	case e.Is(in.FLATPASSWORD_OK):
+		dbg.Printf("password accepted")
+	case e.Is(in.DOORBELL_RINGING):
+		dbg.Printf("ringing doorbell")
+	case e.Is(in.FLATNOTALLOWEDTOENTER):
+		return true, ErrDoorbellDenied
+	case e.Is(in.USER_BANNED):
+		return true, ErrBanned
+	case e.Is(in.CANTCONNECT):
+		switch reason := e.Packet.ReadInt(); reason {
+		case cantConnectFull:
+			return true, ErrRoomFull
+		case cantConnectBanned:
+			return true, ErrBanned
+		default:
+			dbg.Printf("cannot connect (reason: %d)", reason)
+			return true, ErrCantConnect
+		}
+	case e.Is(in.ERROR):
+		// A rejected password is reported as an error, e.g. "Incorrect flat password".
+		// Other errors are unrelated to entering the room.
+		msg := e.Packet.ReadString()
+		if isPasswordError(msg) {
+			return true, fmt.Errorf("%w: %s", ErrWrongPassword, msg)
+		}
+		dbg.Printf("ignoring error while entering room: %q", msg)
+	}
+	return false, nil
+}
+
+// isPasswordError reports whether the error message indicates a rejected room password.
+func isPasswordError(msg string) bool {
+	return strings.Contains(strings.ToLower(msg), "password")
+}
+
+func enterCtxErr(ctx context.Context) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return ErrTimeout
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,145 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	g "xabbo.b7c.io/goearth"
+	"xabbo.b7c.io/goearth/internal/testix"
+	"xabbo.b7c.io/goearth/shockwave/in"
+	"xabbo.b7c.io/goearth/shockwave/out"
+)
+
+// loadRoom responds to GOTOFLAT by loading room 123 with one object and one entity.
+func loadRoom(ix *testix.Interceptor, _ *g.Packet) {
+	ix.Dispatch(in.ROOM_READY, "model_a 123")
+	ix.Dispatch(in.ACTIVEOBJECTS, g.Length(1),
+		"5", "chair", 1, 2, 1, 1, 4, "0.0", "", "", 0, "")
+	ix.Dispatch(in.USERS, g.Length(1), &Entity{EntityBase: EntityBase{
+		Index: 0, Name: "user", Tile: Tile{X: 3, Y: 4}, Type: User,
+	}})
+}
+
+func dispatch(id g.Identifier, values ...any) testix.Responder {
+	return func(ix *testix.Interceptor, _ *g.Packet) { ix.Dispatch(id, values...) }
+}
+
+// newEnterTest creates a room manager whose server responds to TRYFLAT with the specified responses,
+// and to GOTOFLAT by loading the room.
+func newEnterTest(responses ...testix.Responder) (*testix.Interceptor, *Manager) {
+	ix := testix.New(g.Shockwave)
+	ix.Resolve(in.FLAT_LETIN, in.FLATPASSWORD_OK, in.DOORBELL_RINGING, in.FLATNOTALLOWEDTOENTER,
+		in.ERROR, in.CANTCONNECT, in.USER_BANNED, in.ROOM_READY, in.ACTIVEOBJECTS, in.USERS)
+	for _, respond := range responses {
+		ix.Respond(out.TRYFLAT, respond)
+	}
+	ix.Respond(out.GOTOFLAT, loadRoom)
+	return ix, NewManager(ix)
+}
+
+func TestEnter(t *testing.T) {
+	ix, mgr := newEnterTest(
+		dispatch(in.DOORBELL_RINGING, ""),
+		dispatch(in.FLAT_LETIN),
+	)
+	defer ix.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	res, err := mgr.Enter(ctx, 123, "")
+	if err != nil {
+		t.Fatalf("failed to enter room: %s", err)
+	}
+	if res.Id != 123 {
+		t.Errorf("incorrect room ID, expected: %d, actual: %d", 123, res.Id)
+	}
+	if len(res.Objects) != 1 || res.Objects[0].Id != 5 || res.Objects[0].Class != "chair" {
+		t.Errorf("incorrect objects: %+v", res.Objects)
+	}
+	if len(res.Entities) != 1 || res.Entities[0].Name != "user" {
+		t.Errorf("incorrect entities: %+v", res.Entities)
+	}
+
+	tryFlat := ix.SentTo(out.TRYFLAT)
+	if len(tryFlat) != 1 || string(tryFlat[0].Data) != "123/" {
+		t.Errorf("incorrect TRYFLAT packets: %v", tryFlat)
+	}
+	goToFlat := ix.SentTo(out.GOTOFLAT)
+	if len(goToFlat) != 1 || string(goToFlat[0].Data) != "123" {
+		t.Errorf("incorrect GOTOFLAT packets: %v", goToFlat)
+	}
+}
+
+func TestEnterIgnoresUnrelatedError(t *testing.T) {
+	ix, mgr := newEnterTest(
+		dispatch(in.ERROR, "Unrelated error"),
+		dispatch(in.FLAT_LETIN),
+	)
+	defer ix.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := mgr.Enter(ctx, 123, ""); err != nil {
+		t.Fatalf("failed to enter room: %s", err)
+	}
+}
+
+func TestEnterRefused(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		response testix.Responder
+		expected error
+	}{
+		{"wrong password", "secret", dispatch(in.ERROR, "Incorrect flat password"), ErrWrongPassword},
+		{"missing password", "", dispatch(in.ERROR, "Incorrect flat password"), ErrWrongPassword},
+		{"full", "", dispatch(in.CANTCONNECT, cantConnectFull), ErrRoomFull},
+		{"banned", "", dispatch(in.CANTCONNECT, cantConnectBanned), ErrBanned},
+		{"user banned", "", dispatch(in.USER_BANNED), ErrBanned},
+		{"doorbell denied", "", dispatch(in.FLATNOTALLOWEDTOENTER), ErrDoorbellDenied},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ix, mgr := newEnterTest(test.response)
+			defer ix.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			_, err := mgr.Enter(ctx, 123, test.password)
+			if !errors.Is(err, test.expected) {
+				t.Fatalf("incorrect error, expected: %v, actual: %v", test.expected, err)
+			}
+			if sent := ix.SentTo(out.GOTOFLAT); len(sent) != 0 {
+				t.Fatalf("GOTOFLAT was sent after entry was refused")
+			}
+		})
+	}
+}
+
+func TestEnterTimeout(t *testing.T) {
+	ix, mgr := newEnterTest(dispatch(in.DOORBELL_RINGING, ""))
+	defer ix.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := mgr.Enter(ctx, 123, "")
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("incorrect error, expected: %v, actual: %v", ErrTimeout, err)
+	}
+
+	// Late responses must not block the dispatcher once Enter has returned.
+	done := make(chan struct{})
+	go func() {
+		ix.Dispatch(in.FLAT_LETIN)
+		ix.Dispatch(in.ROOM_READY, "model_a 123")
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("dispatcher blocked after Enter returned")
+	}
+}
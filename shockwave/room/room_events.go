@@ -24,6 +24,11 @@ type ObjectsArgs struct {
 	Objects []Object
 }
 
+// PassiveObjectsArgs holds the arguments for passive object events.
+type PassiveObjectsArgs struct {
+	Objects []PassiveObject
+}
+
 // SlideArgs holds the arguments for floor item and entity slide events.
 type SlideArgs struct {
 	From, To     Point
@@ -88,82 +93,194 @@ type EntityChatArgs struct {
 	Message string
 }
 
+// DoorbellArgs holds the arguments for doorbell events.
+type DoorbellArgs struct {
+	Name string
+}
+
 // Entered registers an event handler that is invoked when the user enters a room.
-func (mgr *Manager) Entered(handler g.EventHandler[Args]) {
-	mgr.entered.Register(handler)
+func (mgr *Manager) Entered(handlers ...g.EventHandler[Args]) *g.Event[Args] {
+	mgr.entered.Register(handlers...)
+	return &mgr.entered
 }
 
-func (mgr *Manager) RightsUpdated(handler g.VoidHandler) {
-	mgr.rightsUpdated.Register(handler)
+func (mgr *Manager) RightsUpdated(handlers ...g.VoidHandler) *g.VoidEvent {
+	mgr.rightsUpdated.Register(handlers...)
+	return &mgr.rightsUpdated
+}
+
+// DoorbellRang registers an event handler that is invoked when a user rings the doorbell of the current room.
+func (mgr *Manager) DoorbellRang(handlers ...g.EventHandler[DoorbellArgs]) *g.Event[DoorbellArgs] {
+	mgr.doorbellRang.Register(handlers...)
+	return &mgr.doorbellRang
+}
+
+// LetIn registers an event handler that is invoked when the user is let into a room after ringing its doorbell.
+func (mgr *Manager) LetIn(handlers ...g.VoidHandler) *g.VoidEvent {
+	mgr.letIn.Register(handlers...)
+	return &mgr.letIn
 }
 
 // ObjectsLoaded registers an event handler that is invoked when floor items are loaded.
-func (mgr *Manager) ObjectsLoaded(handler g.EventHandler[ObjectsArgs]) {
-	mgr.objectsLoaded.Register(handler)
+func (mgr *Manager) ObjectsLoaded(handlers ...g.EventHandler[ObjectsArgs]) *g.Event[ObjectsArgs] {
+	mgr.objectsLoaded.Register(handlers...)
+	return &mgr.objectsLoaded
+}
+
+// PassiveObjectsLoaded registers an event handler that is invoked when the passive objects of a public room are loaded.
+func (mgr *Manager) PassiveObjectsLoaded(handlers ...g.EventHandler[PassiveObjectsArgs]) *g.Event[PassiveObjectsArgs] {
+	mgr.passiveLoaded.Register(handlers...)
+	return &mgr.passiveLoaded
 }
 
 // ObjectAdded registers an event handler that is invoked when a floor item is added to the room.
-func (mgr *Manager) ObjectAdded(handler g.EventHandler[ObjectArgs]) {
-	mgr.objectAdded.Register(handler)
+func (mgr *Manager) ObjectAdded(handlers ...g.EventHandler[ObjectArgs]) *g.Event[ObjectArgs] {
+	mgr.objectAdded.Register(handlers...)
+	return &mgr.objectAdded
 }
 
 // ObjectUpdated registers an event handler that is invoked when a floor item is updated in the room.
-func (mgr *Manager) ObjectUpdated(handler g.EventHandler[ObjectUpdateArgs]) {
-	mgr.objectUpdated.Register(handler)
+func (mgr *Manager) ObjectUpdated(handlers ...g.EventHandler[ObjectUpdateArgs]) *g.Event[ObjectUpdateArgs] {
+	mgr.objectUpdated.Register(handlers...)
+	return &mgr.objectUpdated
+}
+
+// ObjectStateChanged registers an event handler that is invoked when the state of a floor item changes,
+// e.g. when a switch is toggled, a gate is opened or a dice lands.
+func (mgr *Manager) ObjectStateChanged(handlers ...g.EventHandler[ObjectUpdateArgs]) *g.Event[ObjectUpdateArgs] {
+	mgr.objectState.Register(handlers...)
+	return &mgr.objectState
+}
+
+// DiceRolling registers an event handler that is invoked when a dice starts rolling.
+func (mgr *Manager) DiceRolling(handlers ...g.EventHandler[ObjectArgs]) *g.Event[ObjectArgs] {
+	mgr.diceRolling.Register(handlers...)
+	return &mgr.diceRolling
 }
 
 // ObjectRemoved registers an event handler that is invoked when a floor item is removed from the room.
-func (mgr *Manager) ObjectRemoved(handler g.EventHandler[ObjectArgs]) {
-	mgr.objectRemoved.Register(handler)
+func (mgr *Manager) ObjectRemoved(handlers ...g.EventHandler[ObjectArgs]) *g.Event[ObjectArgs] {
+	mgr.objectRemoved.Register(handlers...)
+	return &mgr.objectRemoved
 }
 
 // Slide registers an event handler that is invoked when floor items or an entity slides, e.g. along a roller.
-func (mgr *Manager) Slide(handler g.EventHandler[SlideArgs]) {
-	mgr.slide.Register(handler)
+func (mgr *Manager) Slide(handlers ...g.EventHandler[SlideArgs]) *g.Event[SlideArgs] {
+	mgr.slide.Register(handlers...)
+	return &mgr.slide
 }
 
 // ItemsLoaded registers an event handler that is invoked when wall items are loaded.
-func (mgr *Manager) ItemsLoaded(handler g.EventHandler[ItemsArgs]) {
-	mgr.itemsLoaded.Register(handler)
+func (mgr *Manager) ItemsLoaded(handlers ...g.EventHandler[ItemsArgs]) *g.Event[ItemsArgs] {
+	mgr.itemsLoaded.Register(handlers...)
+	return &mgr.itemsLoaded
 }
 
 // ItemAdded registers an event handler that is invoked when a wall item is added to the room.
-func (mgr *Manager) ItemAdded(handler g.EventHandler[ItemArgs]) {
-	mgr.itemAdded.Register(handler)
+func (mgr *Manager) ItemAdded(handlers ...g.EventHandler[ItemArgs]) *g.Event[ItemArgs] {
+	mgr.itemAdded.Register(handlers...)
+	return &mgr.itemAdded
 }
 
 // ItemUpdated registers an event handler that is invoked when a wall item is updated in the room.
-func (mgr *Manager) ItemUpdated(handler g.EventHandler[ItemUpdateArgs]) {
-	mgr.itemUpdated.Register(handler)
+func (mgr *Manager) ItemUpdated(handlers ...g.EventHandler[ItemUpdateArgs]) *g.Event[ItemUpdateArgs] {
+	mgr.itemUpdated.Register(handlers...)
+	return &mgr.itemUpdated
 }
 
 // ItemRemoved registers an event handler that is invoked when an item is removed from the room.
-func (mgr *Manager) ItemRemoved(handler g.EventHandler[ItemArgs]) {
-	mgr.itemRemoved.Register(handler)
+func (mgr *Manager) ItemRemoved(handlers ...g.EventHandler[ItemArgs]) *g.Event[ItemArgs] {
+	mgr.itemRemoved.Register(handlers...)
+	return &mgr.itemRemoved
 }
 
 // EntitiesAdded registers an event handler that is invoked when entities are loaded or enter the room.
 // The Entered flag on the EntitiesArgs indicates whether the entity entered the room.
 // If not, the entities were already in the room and are being loaded.
-func (mgr *Manager) EntitiesAdded(handler g.EventHandler[EntitiesArgs]) {
-	mgr.entitiesAdded.Register(handler)
+func (mgr *Manager) EntitiesAdded(handlers ...g.EventHandler[EntitiesArgs]) *g.Event[EntitiesArgs] {
+	mgr.entitiesAdded.Register(handlers...)
+	return &mgr.entitiesAdded
+}
+
+func (mgr *Manager) EntityUpdated(handlers ...g.EventHandler[EntityUpdateArgs]) *g.Event[EntityUpdateArgs] {
+	mgr.entityUpdated.Register(handlers...)
+	return &mgr.entityUpdated
+}
+
+// EntityMoved registers an event handler that is invoked when an entity moves to another tile.
+func (mgr *Manager) EntityMoved(handlers ...g.EventHandler[EntityUpdateArgs]) *g.Event[EntityUpdateArgs] {
+	mgr.entityMoved.Register(handlers...)
+	return &mgr.entityMoved
+}
+
+// EntitySat registers an event handler that is invoked when an entity sits down.
+func (mgr *Manager) EntitySat(handlers ...g.EventHandler[EntityUpdateArgs]) *g.Event[EntityUpdateArgs] {
+	mgr.entitySat.Register(handlers...)
+	return &mgr.entitySat
+}
+
+// EntityLay registers an event handler that is invoked when an entity lies down.
+func (mgr *Manager) EntityLay(handlers ...g.EventHandler[EntityUpdateArgs]) *g.Event[EntityUpdateArgs] {
+	mgr.entityLay.Register(handlers...)
+	return &mgr.entityLay
+}
+
+// EntityStood registers an event handler that is invoked when an entity stands up after sitting or lying.
+func (mgr *Manager) EntityStood(handlers ...g.EventHandler[EntityUpdateArgs]) *g.Event[EntityUpdateArgs] {
+	mgr.entityStood.Register(handlers...)
+	return &mgr.entityStood
+}
+
+// EntityStartedDancing registers an event handler that is invoked when an entity starts dancing.
+func (mgr *Manager) EntityStartedDancing(handlers ...g.EventHandler[EntityUpdateArgs]) *g.Event[EntityUpdateArgs] {
+	mgr.entityDanceStart.Register(handlers...)
+	return &mgr.entityDanceStart
+}
+
+// EntityStoppedDancing registers an event handler that is invoked when an entity stops dancing.
+func (mgr *Manager) EntityStoppedDancing(handlers ...g.EventHandler[EntityUpdateArgs]) *g.Event[EntityUpdateArgs] {
+	mgr.entityDanceStop.Register(handlers...)
+	return &mgr.entityDanceStop
+}
+
+// EntityWaved registers an event handler that is invoked when an entity waves.
+func (mgr *Manager) EntityWaved(handlers ...g.EventHandler[EntityUpdateArgs]) *g.Event[EntityUpdateArgs] {
+	mgr.entityWaved.Register(handlers...)
+	return &mgr.entityWaved
+}
+
+// EntitySign registers an event handler that is invoked when an entity holds up a sign.
+func (mgr *Manager) EntitySign(handlers ...g.EventHandler[EntityUpdateArgs]) *g.Event[EntityUpdateArgs] {
+	mgr.entitySigned.Register(handlers...)
+	return &mgr.entitySigned
+}
+
+// EntityCarried registers an event handler that is invoked when an entity starts carrying an item.
+func (mgr *Manager) EntityCarried(handlers ...g.EventHandler[EntityUpdateArgs]) *g.Event[EntityUpdateArgs] {
+	mgr.entityCarried.Register(handlers...)
+	return &mgr.entityCarried
 }
 
-func (mgr *Manager) EntityUpdated(handler g.EventHandler[EntityUpdateArgs]) {
-	mgr.entityUpdated.Register(handler)
+// EntityGesture registers an event handler that is invoked when an entity shows a gesture.
+func (mgr *Manager) EntityGesture(handlers ...g.EventHandler[EntityUpdateArgs]) *g.Event[EntityUpdateArgs] {
+	mgr.entityGesture.Register(handlers...)
+	return &mgr.entityGesture
 }
 
 // EntityChat registers an event handler that is invoked when an entity sends a chat message.
-func (mgr *Manager) EntityChat(handler g.EventHandler[EntityChatArgs]) {
-	mgr.entityChat.Register(handler)
+func (mgr *Manager) EntityChat(handlers ...g.EventHandler[EntityChatArgs]) *g.Event[EntityChatArgs] {
+	mgr.entityChat.Register(handlers...)
+	return &mgr.entityChat
 }
 
 // EntityLeft registers an event handler that is invoked when an entity leaves the room.
-func (mgr *Manager) EntityLeft(handler g.EventHandler[EntityArgs]) {
-	mgr.entityLeft.Register(handler)
+func (mgr *Manager) EntityLeft(handlers ...g.EventHandler[EntityArgs]) *g.Event[EntityArgs] {
+	mgr.entityLeft.Register(handlers...)
+	return &mgr.entityLeft
 }
 
 // Left registers an event handler that is invoked when the user leaves the room.
-func (mgr *Manager) Left(handler g.EventHandler[Args]) {
-	mgr.left.Register(handler)
+func (mgr *Manager) Left(handlers ...g.EventHandler[Args]) *g.Event[Args] {
+	mgr.left.Register(handlers...)
+	return &mgr.left
 }
@@ -1,6 +1,7 @@
 package room
 
 import (
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -15,22 +16,37 @@ var dbg = debug.NewLogger("[room]")
 type Manager struct {
 	ix g.Interceptor
 
-	entered       g.Event[Args]
-	rightsUpdated g.VoidEvent
-	objectsLoaded g.Event[ObjectsArgs]
-	objectAdded   g.Event[ObjectArgs]
-	objectUpdated g.Event[ObjectUpdateArgs]
-	objectRemoved g.Event[ObjectArgs]
-	slide         g.Event[SlideArgs]
-	itemsLoaded   g.Event[ItemsArgs]
-	itemAdded     g.Event[ItemArgs]
-	itemUpdated   g.Event[ItemUpdateArgs]
-	itemRemoved   g.Event[ItemArgs]
-	entitiesAdded g.Event[EntitiesArgs]
-	entityUpdated g.Event[EntityUpdateArgs]
-	entityChat    g.Event[EntityChatArgs]
-	entityLeft    g.Event[EntityArgs]
-	left          g.Event[Args]
+	entered          g.Event[Args]
+	rightsUpdated    g.VoidEvent
+	doorbellRang     g.Event[DoorbellArgs]
+	letIn            g.VoidEvent
+	objectsLoaded    g.Event[ObjectsArgs]
+	passiveLoaded    g.Event[PassiveObjectsArgs]
+	objectAdded      g.Event[ObjectArgs]
+	objectUpdated    g.Event[ObjectUpdateArgs]
+	objectState      g.Event[ObjectUpdateArgs]
+	diceRolling      g.Event[ObjectArgs]
+	objectRemoved    g.Event[ObjectArgs]
+	slide            g.Event[SlideArgs]
+	itemsLoaded      g.Event[ItemsArgs]
+	itemAdded        g.Event[ItemArgs]
+	itemUpdated      g.Event[ItemUpdateArgs]
+	itemRemoved      g.Event[ItemArgs]
+	entitiesAdded    g.Event[EntitiesArgs]
+	entityUpdated    g.Event[EntityUpdateArgs]
+	entityMoved      g.Event[EntityUpdateArgs]
+	entitySat        g.Event[EntityUpdateArgs]
+	entityLay        g.Event[EntityUpdateArgs]
+	entityStood      g.Event[EntityUpdateArgs]
+	entityDanceStart g.Event[EntityUpdateArgs]
+	entityDanceStop  g.Event[EntityUpdateArgs]
+	entityWaved      g.Event[EntityUpdateArgs]
+	entitySigned     g.Event[EntityUpdateArgs]
+	entityCarried    g.Event[EntityUpdateArgs]
+	entityGesture    g.Event[EntityUpdateArgs]
+	entityChat       g.Event[EntityChatArgs]
+	entityLeft       g.Event[EntityArgs]
+	left             g.Event[Args]
 
 	mtxCache  *sync.RWMutex
 	infoCache map[int]Info
@@ -44,38 +60,41 @@ type Manager struct {
 	roomInfo  *Info
 	isOwner   bool // IsOwner indicates whether the user is the owner of the current room.
 	hasRights bool // HasRights indicates whether the user has rights in the current room.
-	heightmap []string
-
-	mtxObjs  *sync.RWMutex
-	objects  map[int]Object
-	mtxItems *sync.RWMutex
-	items    map[int]Item
-	mtxEnts  *sync.RWMutex
-	entities map[int]Entity
+	heightmap *Heightmap
+
+	mtxObjs    *sync.RWMutex
+	objects    map[int]Object
+	passive    []PassiveObject
+	objectInfo ObjectInfoFunc
+	mtxItems   *sync.RWMutex
+	items      map[int]Item
+	mtxEnts    *sync.RWMutex
+	entities   map[int]Entity
+	names      map[string]int   // names maps lower-case entity names to their index.
+	figures    map[string][]int // figures maps figure strings to the indexes of entities wearing them.
+
+	mtxHist     *sync.RWMutex
+	history     HistoryStore
+	histQueue   []UserRecord
+	histFlushed chan struct{} // histFlushed is closed once the queued records have been stored.
 }
 
 func NewManager(ix g.Interceptor) *Manager {
-	mgr := &Manager{
-		ix:        ix,
-		mtxRoom:   &sync.RWMutex{},
-		mtxCache:  &sync.RWMutex{},
-		infoCache: map[int]Info{},
-		mtxObjs:   &sync.RWMutex{},
-		objects:   map[int]Object{},
-		mtxItems:  &sync.RWMutex{},
-		items:     map[int]Item{},
-		mtxEnts:   &sync.RWMutex{},
-		entities:  map[int]Entity{},
-	}
+	mgr := newManager(ix)
 	ix.Intercept(in.FLATINFO).With(mgr.handleFlatInfo)
 	ix.Intercept(in.OPC_OK).With(mgr.handleOpcOk)
 	ix.Intercept(in.ROOM_READY).With(mgr.handleRoomReady)
 	ix.Intercept(in.ROOM_RIGHTS, in.ROOM_RIGHTS_2, in.ROOM_RIGHTS_3).With(mgr.handleRoomRights)
-	ix.Intercept(in.HEIGHTMAP).With(mgr.handleHeightmap)
+	ix.Intercept(in.DOORBELL_RINGING).With(mgr.handleDoorbellRinging)
+	ix.Intercept(in.FLAT_LETIN).With(mgr.handleFlatLetIn)
+	ix.Intercept(in.HEIGHTMAP, in.HEIGHTMAPUPDATE).With(mgr.handleHeightmap)
+	ix.Intercept(in.OBJECTS).With(mgr.handleObjects)
 	ix.Intercept(in.ACTIVEOBJECTS).With(mgr.handleActiveObjects)
 	ix.Intercept(in.ACTIVEOBJECT_ADD).With(mgr.handleActiveObjectAdd)
 	ix.Intercept(in.ACTIVEOBJECT_UPDATE).With(mgr.handleActiveObjectUpdate)
 	ix.Intercept(in.ACTIVEOBJECT_REMOVE).With(mgr.handleActiveObjectRemove)
+	ix.Intercept(in.STUFFDATAUPDATE).With(mgr.handleStuffDataUpdate)
+	ix.Intercept(in.DICE_VALUE).With(mgr.handleDiceValue)
 	ix.Intercept(in.SLIDEOBJECTBUNDLE).With(mgr.handleSlideObjectBundle)
 	ix.Intercept(in.ITEMS).With(mgr.handleItems)
 	ix.Intercept(in.ITEMS_2, in.UPDATEITEM).With(mgr.handleAddOrUpdateItem)
@@ -88,6 +107,24 @@ func NewManager(ix g.Interceptor) *Manager {
 	return mgr
 }
 
+func newManager(ix g.Interceptor) *Manager {
+	return &Manager{
+		ix:        ix,
+		mtxRoom:   &sync.RWMutex{},
+		mtxCache:  &sync.RWMutex{},
+		infoCache: map[int]Info{},
+		mtxObjs:   &sync.RWMutex{},
+		objects:   map[int]Object{},
+		mtxItems:  &sync.RWMutex{},
+		items:     map[int]Item{},
+		mtxEnts:   &sync.RWMutex{},
+		entities:  map[int]Entity{},
+		names:     map[string]int{},
+		figures:   map[string][]int{},
+		mtxHist:   &sync.RWMutex{},
+	}
+}
+
 func (mgr *Manager) IsInRoom() bool {
 	return mgr.isInRoom
 }
@@ -112,8 +149,82 @@ func (mgr *Manager) HasRights() bool {
 	return mgr.hasRights
 }
 
-func (mgr *Manager) Heightmap() []string {
-	return mgr.heightmap
+// Heightmap returns a copy of the current room's heightmap, or nil if it has not been received.
+func (mgr *Manager) Heightmap() *Heightmap {
+	mgr.mtxRoom.RLock()
+	defer mgr.mtxRoom.RUnlock()
+	if mgr.heightmap == nil {
+		return nil
+	}
+	return mgr.heightmap.Clone()
+}
+
+// SetObjectInfo sets the function used to determine whether floor items can be walked on
+// and how far they raise the tiles they occupy when building the walk map.
+// The client does not receive this information from the server, so it is typically looked up
+// from the furniture data by class. If no function is set, every floor item blocks its tiles.
+func (mgr *Manager) SetObjectInfo(info ObjectInfoFunc) {
+	mgr.mtxObjs.Lock()
+	defer mgr.mtxObjs.Unlock()
+	mgr.objectInfo = info
+}
+
+// WalkMap returns a copy of the current room's heightmap where
+// the tiles occupied by non-walkable floor items, passive objects and entities are marked as blocked,
+// and the heights of tiles with stacked floor items are raised to the top of the stack.
+// Returns nil if the heightmap has not been received.
+func (mgr *Manager) WalkMap() *Heightmap {
+	hm := mgr.Heightmap()
+	if hm == nil {
+		return nil
+	}
+	mgr.mtxObjs.RLock()
+	objs := make([]Object, 0, len(mgr.objects))
+	for _, obj := range mgr.objects {
+		objs = append(objs, obj)
+	}
+	info := mgr.objectInfo
+	for _, obj := range mgr.passive {
+		hm.Block(obj.X, obj.Y)
+	}
+	mgr.mtxObjs.RUnlock()
+	hm.PlaceObjects(objs, info)
+	mgr.mtxEnts.RLock()
+	for _, ent := range mgr.entities {
+		hm.Block(ent.X, ent.Y)
+	}
+	mgr.mtxEnts.RUnlock()
+	return hm
+}
+
+// FindPath finds a path between two points in the current room, avoiding floor items and entities.
+// The destination tile is allowed to be occupied, e.g. by a chair.
+// Returns nil if no path exists or the heightmap has not been received.
+func (mgr *Manager) FindPath(from, to Point) []Point {
+	hm := mgr.WalkMap()
+	if hm == nil {
+		return nil
+	}
+	if !mgr.heightmapTile(to).Blocked {
+		tile := hm.At(to.X, to.Y)
+		tile.Blocked = false
+		hm.Set(to.X, to.Y, tile)
+	}
+	return hm.FindPath(from, to)
+}
+
+// CanReach returns whether the specified point can be reached from another point in the current room.
+func (mgr *Manager) CanReach(from, to Point) bool {
+	return from == to || mgr.FindPath(from, to) != nil
+}
+
+func (mgr *Manager) heightmapTile(pt Point) HeightmapTile {
+	mgr.mtxRoom.RLock()
+	defer mgr.mtxRoom.RUnlock()
+	if mgr.heightmap == nil {
+		return HeightmapTile{Blocked: true}
+	}
+	return mgr.heightmap.At(pt.X, pt.Y)
 }
 
 // Object gets a floor item in the room by its ID.
@@ -147,6 +258,41 @@ func (mgr *Manager) ObjectCount() int {
 	return len(mgr.objects)
 }
 
+// PassiveObject gets the passive object at the specified point in the room.
+// It returns nil if there is no passive object at the point.
+func (mgr *Manager) PassiveObject(pt Point) *PassiveObject {
+	mgr.mtxObjs.RLock()
+	defer mgr.mtxObjs.RUnlock()
+
+	for _, obj := range mgr.passive {
+		if obj.X == pt.X && obj.Y == pt.Y {
+			return &obj
+		}
+	}
+	return nil
+}
+
+// PassiveObjects iterates over all passive objects currently in the room.
+// Passive objects are the static objects in public rooms, such as seats and obstacles.
+func (mgr *Manager) PassiveObjects(yield func(obj PassiveObject) bool) {
+	mgr.mtxObjs.RLock()
+	for _, obj := range mgr.passive {
+		mgr.mtxObjs.RUnlock()
+		if !yield(obj) {
+			return
+		}
+		mgr.mtxObjs.RLock()
+	}
+	mgr.mtxObjs.RUnlock()
+}
+
+// PassiveObjectCount returns the number of passive objects in the room.
+func (mgr *Manager) PassiveObjectCount() int {
+	mgr.mtxObjs.RLock()
+	defer mgr.mtxObjs.RUnlock()
+	return len(mgr.passive)
+}
+
 // Item gets a wall item in the room by its ID.
 // It returns nil if the item was not found.
 func (mgr *Manager) Item(id int) *Item {
@@ -195,18 +341,31 @@ func (mgr *Manager) Entity(id int) *Entity {
 // Names are case-insensitive.
 // Returns nil if it does not exist.
 func (mgr *Manager) EntityByName(name string) *Entity {
-	// TODO add name -> entity map
 	mgr.mtxEnts.RLock()
 	defer mgr.mtxEnts.RUnlock()
 
-	for _, ent := range mgr.entities {
-		if strings.EqualFold(ent.Name, name) {
+	if index, ok := mgr.names[strings.ToLower(name)]; ok {
+		if ent, ok := mgr.entities[index]; ok {
 			return &ent
 		}
 	}
 	return nil
 }
 
+// EntitiesByFigure returns the entities with the specified figure.
+func (mgr *Manager) EntitiesByFigure(figure string) []Entity {
+	mgr.mtxEnts.RLock()
+	defer mgr.mtxEnts.RUnlock()
+
+	var ents []Entity
+	for _, index := range mgr.figures[figure] {
+		if ent, ok := mgr.entities[index]; ok {
+			ents = append(ents, ent)
+		}
+	}
+	return ents
+}
+
 // Entities iterates over all entities currently in the room.
 func (mgr *Manager) Entities(yield func(ent Entity) bool) {
 	mgr.mtxEnts.RLock()
@@ -245,11 +404,17 @@ func (mgr *Manager) enterRoom(model string, id int) (info Info, ok bool) {
 
 func (mgr *Manager) leaveRoom() {
 	if mgr.isInRoom {
+		var id int
+		var ents []Entity
+		// The users are recorded once the room lock has been released.
+		defer func() { mgr.recordHistory(id, ents) }()
+
 		mgr.mtxRoom.Lock()
 		defer mgr.mtxRoom.Unlock()
 
-		id := mgr.roomId
+		id = mgr.roomId
 		info := mgr.roomInfo
+		ents = slices.Collect(mgr.Entities)
 
 		mgr.usersPacketCount = 0
 
@@ -259,7 +424,7 @@ func (mgr *Manager) leaveRoom() {
 		mgr.roomInfo = nil
 		mgr.isOwner = false
 		mgr.hasRights = false
-		mgr.heightmap = []string{}
+		mgr.heightmap = nil
 		mgr.clearObjects()
 		mgr.clearItems()
 		mgr.clearEntities()
@@ -399,6 +564,15 @@ func (mgr *Manager) clearObjects() {
 	defer mgr.mtxObjs.Unlock()
 	clear(mgr.objects)
 	mgr.objects = map[int]Object{}
+	mgr.passive = nil
+}
+
+func (mgr *Manager) setPassiveObjects(objs []PassiveObject) {
+	mgr.mtxObjs.Lock()
+	defer mgr.mtxObjs.Unlock()
+
+	mgr.passive = objs
+	dbg.Printf("loaded %d passive objects", len(objs))
 }
 
 func (mgr *Manager) addItems(load bool, items []Item) {
@@ -461,10 +635,34 @@ func (mgr *Manager) addEntities(ents []Entity) {
 
 	for _, entity := range ents {
 		// TODO: check if this branch gets optimized away when !debug.Enabled
-		if _, exists := mgr.entities[entity.Index]; exists {
+		if existing, exists := mgr.entities[entity.Index]; exists {
 			dbg.Printf("WARNING: duplicate entity index: %d", entity.Index)
+			mgr.unindexEntity(existing)
 		}
 		mgr.entities[entity.Index] = entity
+		mgr.indexEntity(entity)
+	}
+}
+
+// indexEntity adds the entity to the name and figure indexes.
+// The entity lock must be held.
+func (mgr *Manager) indexEntity(ent Entity) {
+	mgr.names[strings.ToLower(ent.Name)] = ent.Index
+	mgr.figures[ent.Figure] = append(mgr.figures[ent.Figure], ent.Index)
+}
+
+// unindexEntity removes the entity from the name and figure indexes.
+// The entity lock must be held.
+func (mgr *Manager) unindexEntity(ent Entity) {
+	name := strings.ToLower(ent.Name)
+	if mgr.names[name] == ent.Index {
+		delete(mgr.names, name)
+	}
+	indexes := slices.DeleteFunc(mgr.figures[ent.Figure], func(index int) bool { return index == ent.Index })
+	if len(indexes) > 0 {
+		mgr.figures[ent.Figure] = indexes
+	} else {
+		delete(mgr.figures, ent.Figure)
 	}
 }
 
@@ -482,6 +680,7 @@ func (mgr *Manager) updateEntities(statuses []EntityStatus) []EntityUpdateArgs {
 			cur.Dir = status.BodyDir
 			cur.HeadDir = status.HeadDir
 			cur.Action = status.Action
+			cur.Statuses = status.Statuses()
 			mgr.entities[status.Index] = cur
 			updates = append(updates, EntityUpdateArgs{Pre: pre, Entity: cur})
 		} else {
@@ -498,6 +697,7 @@ func (mgr *Manager) removeEntity(index int) (ent Entity, ok bool) {
 
 	if ent, ok = mgr.entities[index]; ok {
 		delete(mgr.entities, index)
+		mgr.unindexEntity(ent)
 		dbg.Printf("removed entity %q (index: %d)", ent.Name, ent.Index)
 	} else {
 		dbg.Printf("WARNING: failed to find entity to remove (index: %d)", index)
@@ -511,6 +711,8 @@ func (mgr *Manager) clearEntities() {
 	defer mgr.mtxEnts.Unlock()
 	clear(mgr.entities)
 	mgr.entities = map[int]Entity{}
+	mgr.names = map[string]int{}
+	mgr.figures = map[string][]int{}
 }
 
 // handlers
@@ -576,20 +778,53 @@ func (mgr *Manager) handleRoomRights(e *g.Intercept) {
 	}
 }
 
+func (mgr *Manager) handleDoorbellRinging(e *g.Intercept) {
+	name := e.Packet.ReadString()
+	if name == "" {
+		// Sent to the user ringing the doorbell.
+		dbg.Printf("waiting for doorbell to be answered")
+		return
+	}
+
+	mgr.doorbellRang.Dispatch(DoorbellArgs{Name: name})
+	dbg.Printf("%q rang the doorbell", name)
+}
+
+func (mgr *Manager) handleFlatLetIn(e *g.Intercept) {
+	mgr.letIn.Dispatch()
+	dbg.Printf("let in to room")
+}
+
 func (mgr *Manager) handleHeightmap(e *g.Intercept) {
 	if !mgr.isInRoom {
 		return
 	}
 
-	mgr.heightmap = strings.Split(e.Packet.ReadString(), "\r")
+	var hm Heightmap
+	e.Packet.Read(&hm)
 
-	if debug.Enabled {
-		if len(mgr.heightmap) > 0 {
-			dbg.Printf("received heightmap (%dx%d)", len(mgr.heightmap[0]), len(mgr.heightmap))
-		} else {
-			dbg.Println("WARNING: empty heightmap")
-		}
+	mgr.mtxRoom.Lock()
+	mgr.heightmap = &hm
+	mgr.mtxRoom.Unlock()
+
+	if hm.Width > 0 && hm.Length > 0 {
+		dbg.Printf("received heightmap (%dx%d, update: %t)", hm.Width, hm.Length, e.Is(in.HEIGHTMAPUPDATE))
+	} else {
+		dbg.Println("WARNING: empty heightmap")
+	}
+}
+
+func (mgr *Manager) handleObjects(e *g.Intercept) {
+	if !mgr.isInRoom {
+		return
 	}
+
+	var objects []PassiveObject
+	e.Packet.Read(&objects)
+
+	mgr.setPassiveObjects(objects)
+
+	mgr.passiveLoaded.Dispatch(PassiveObjectsArgs{Objects: objects})
 }
 
 func (mgr *Manager) handleActiveObjects(e *g.Intercept) {
@@ -628,7 +863,68 @@ func (mgr *Manager) handleActiveObjectUpdate(e *g.Intercept) {
 
 	if pre, ok := mgr.updateObject(cur); ok {
 		mgr.objectUpdated.Dispatch(ObjectUpdateArgs{Pre: pre, Object: cur})
+		if pre.StuffData != cur.StuffData {
+			mgr.objectState.Dispatch(ObjectUpdateArgs{Pre: pre, Object: cur})
+		}
+	}
+}
+
+func (mgr *Manager) handleStuffDataUpdate(e *g.Intercept) {
+	if !mgr.isInRoom {
+		return
+	}
+
+	strId, data := e.Packet.ReadString(), e.Packet.ReadString()
+	id, err := strconv.Atoi(strId)
+	if err != nil {
+		dbg.Printf("WARNING: invalid object ID: %q", strId)
+		return
 	}
+
+	mgr.updateObjectState(id, data)
+}
+
+func (mgr *Manager) handleDiceValue(e *g.Intercept) {
+	if !mgr.isInRoom {
+		return
+	}
+
+	s := e.Packet.ReadString()
+	id, value, rolling, ok := parseDiceValue(s)
+	if !ok {
+		dbg.Printf("WARNING: invalid dice value: %q", s)
+		return
+	}
+
+	if rolling {
+		if obj := mgr.Object(id); obj != nil {
+			mgr.diceRolling.Dispatch(ObjectArgs{Object: *obj})
+		}
+		return
+	}
+
+	mgr.updateObjectState(id, strconv.Itoa(value))
+}
+
+// updateObjectState updates the stuff data of the object with the specified ID
+// and dispatches the object state changed event.
+func (mgr *Manager) updateObjectState(id int, data string) {
+	mgr.mtxObjs.Lock()
+	pre, ok := mgr.objects[id]
+	cur := pre
+	if ok {
+		cur.StuffData = data
+		mgr.objects[id] = cur
+	}
+	mgr.mtxObjs.Unlock()
+
+	if !ok {
+		dbg.Printf("WARNING: failed to find object to update state (ID: %d)", id)
+		return
+	}
+
+	dbg.Printf("updated object state %s (ID: %d): %q", cur.Class, cur.Id, data)
+	mgr.objectState.Dispatch(ObjectUpdateArgs{Pre: pre, Object: cur})
 }
 
 func (mgr *Manager) handleActiveObjectRemove(e *g.Intercept) {
@@ -714,6 +1010,7 @@ func (mgr *Manager) handleUsers(e *g.Intercept) {
 	e.Packet.Read(&ents)
 
 	mgr.addEntities(ents)
+	mgr.recordHistory(mgr.roomId, ents)
 
 	if mgr.usersPacketCount < 3 {
 		mgr.usersPacketCount++
@@ -739,10 +1036,47 @@ func (mgr *Manager) handleStatus(e *g.Intercept) {
 
 	for _, update := range updates {
 		mgr.entityUpdated.Dispatch(update)
+		mgr.dispatchStatusEvents(update)
 		dbg.Printf("%s: %s", update.Entity.Name, update.Entity.Action)
 	}
 }
 
+// dispatchStatusEvents dispatches the events for the statuses that changed in the update.
+func (mgr *Manager) dispatchStatusEvents(update EntityUpdateArgs) {
+	pre, cur := update.Pre.Statuses, update.Entity.Statuses
+
+	if update.Pre.Tile != update.Entity.Tile {
+		mgr.entityMoved.Dispatch(update)
+	}
+	if pre.Posture != cur.Posture {
+		switch cur.Posture {
+		case Sit:
+			mgr.entitySat.Dispatch(update)
+		case Lay:
+			mgr.entityLay.Dispatch(update)
+		default:
+			mgr.entityStood.Dispatch(update)
+		}
+	}
+	if !pre.Dancing && cur.Dancing {
+		mgr.entityDanceStart.Dispatch(update)
+	} else if pre.Dancing && !cur.Dancing {
+		mgr.entityDanceStop.Dispatch(update)
+	}
+	if !pre.Waving && cur.Waving {
+		mgr.entityWaved.Dispatch(update)
+	}
+	if cur.Signing && (!pre.Signing || pre.Sign != cur.Sign) {
+		mgr.entitySigned.Dispatch(update)
+	}
+	if cur.Carrying != "" && pre.Carrying != cur.Carrying {
+		mgr.entityCarried.Dispatch(update)
+	}
+	if cur.Gesture != "" && pre.Gesture != cur.Gesture {
+		mgr.entityGesture.Dispatch(update)
+	}
+}
+
 func (mgr *Manager) handleChat(e *g.Intercept) {
 	if !mgr.isInRoom {
 		return
@@ -795,6 +1129,7 @@ func (mgr *Manager) handleLogout(e *g.Intercept) {
 	}
 
 	if entity, ok := mgr.removeEntity(index); ok {
+		mgr.recordHistory(mgr.roomId, []Entity{entity})
 		mgr.entityLeft.Dispatch(EntityArgs{Entity: entity})
 	}
 }
@@ -0,0 +1,100 @@
+package room
+
+import (
+	"errors"
+
+	"xabbo.b7c.io/goearth/shockwave/out"
+)
+
+var (
+	// ErrNotInRoom is returned when an action requires the user to be in a room.
+	ErrNotInRoom = errors.New("not in a room")
+	// ErrNoRights is returned when an action requires the user to have rights in the current room.
+	ErrNoRights = errors.New("no rights in the current room")
+	// ErrNotOwner is returned when an action requires the user to own the current room.
+	ErrNotOwner = errors.New("not the owner of the current room")
+)
+
+// requireRights returns an error if the user does not have rights in the current room.
+// Room owners always have rights.
+func (mgr *Manager) requireRights() error {
+	switch {
+	case !mgr.isInRoom:
+		return ErrNotInRoom
+	case !mgr.isOwner && !mgr.hasRights:
+		return ErrNoRights
+	}
+	return nil
+}
+
+// requireOwner returns an error if the user is not the owner of the current room.
+func (mgr *Manager) requireOwner() error {
+	switch {
+	case !mgr.isInRoom:
+		return ErrNotInRoom
+	case !mgr.isOwner:
+		return ErrNotOwner
+	}
+	return nil
+}
+
+// AssignRights gives rights in the current room to the user with the specified name.
+// The user must be the owner of the room.
+func (mgr *Manager) AssignRights(name string) error {
+	if err := mgr.requireOwner(); err != nil {
+		return err
+	}
+	mgr.ix.Send(out.ASSIGNRIGHTS, []byte(name))
+	return nil
+}
+
+// RemoveRights removes rights in the current room from the user with the specified name.
+// The user must be the owner of the room.
+func (mgr *Manager) RemoveRights(name string) error {
+	if err := mgr.requireOwner(); err != nil {
+		return err
+	}
+	mgr.ix.Send(out.REMOVERIGHTS, []byte(name))
+	return nil
+}
+
+// RemoveAllRights removes rights in the current room from all users.
+// The user must be the owner of the room.
+func (mgr *Manager) RemoveAllRights() error {
+	if err := mgr.requireOwner(); err != nil {
+		return err
+	}
+	mgr.ix.Send(out.REMOVEALLRIGHTS, mgr.roomId)
+	return nil
+}
+
+// Kick kicks the user with the specified name from the current room.
+// The user must have rights in the room.
+func (mgr *Manager) Kick(name string) error {
+	if err := mgr.requireRights(); err != nil {
+		return err
+	}
+	mgr.ix.Send(out.KICKUSER, []byte(name))
+	return nil
+}
+
+// Ban kicks and bans the user with the specified name from the current room.
+// The user must be the owner of the room.
+func (mgr *Manager) Ban(name string) error {
+	if err := mgr.requireOwner(); err != nil {
+		return err
+	}
+	mgr.ix.Send(out.ROOMBAN, []byte(name))
+	return nil
+}
+
+// LetUserIn answers the doorbell for the user with the specified name,
+// letting them in if allow is true, or otherwise turning them away.
+// The user must have rights in the room.
+func (mgr *Manager) LetUserIn(name string, allow bool) error {
+	if err := mgr.requireRights(); err != nil {
+		return err
+	}
+	mgr.ix.Send(out.LETUSERIN, name, allow)
+	return nil
+}
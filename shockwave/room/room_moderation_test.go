@@ -0,0 +1,32 @@
+package room
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestModerationGuards(t *testing.T) {
+	mgr := &Manager{}
+	if err := mgr.Kick("user"); !errors.Is(err, ErrNotInRoom) {
+		t.Fatalf("incorrect error, expected: %v, actual: %v", ErrNotInRoom, err)
+	}
+
+	mgr.isInRoom = true
+	if err := mgr.Kick("user"); !errors.Is(err, ErrNoRights) {
+		t.Fatalf("incorrect error, expected: %v, actual: %v", ErrNoRights, err)
+	}
+
+	mgr.hasRights = true
+	if err := mgr.Ban("user"); !errors.Is(err, ErrNotOwner) {
+		t.Fatalf("incorrect error, expected: %v, actual: %v", ErrNotOwner, err)
+	}
+	if err := mgr.requireRights(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mgr.hasRights = false
+	mgr.isOwner = true
+	if err := mgr.requireRights(); err != nil {
+		t.Fatalf("unexpected error for room owner: %v", err)
+	}
+}
@@ -0,0 +1,218 @@
+package room
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	g "xabbo.b7c.io/goearth"
+	"xabbo.b7c.io/goearth/shockwave/in"
+	"xabbo.b7c.io/goearth/shockwave/nav"
+	"xabbo.b7c.io/goearth/shockwave/out"
+)
+
+var (
+	// ErrTimeout is returned when a request does not receive a response in time.
+	ErrTimeout = errors.New("request timed out")
+	// ErrPasswordRequired is returned when a password-protected door mode is set without a password.
+	ErrPasswordRequired = errors.New("password required")
+	// ErrNotUpdated is returned when the server did not apply a change to a room,
+	// e.g. because the user is not its owner.
+	ErrNotUpdated = errors.New("room was not updated")
+)
+
+// DoorMode represents the access mode of a room.
+type DoorMode int
+
+const (
+	DoorOpen DoorMode = iota
+	// DoorClosed requires users to ring the doorbell to enter.
+	DoorClosed
+	// DoorPassword requires users to enter a password to enter.
+	DoorPassword
+)
+
+func (mode DoorMode) String() string {
+	switch mode {
+	case DoorOpen:
+		return "open"
+	case DoorClosed:
+		return "closed"
+	case DoorPassword:
+		return "password"
+	default:
+		return strconv.Itoa(int(mode))
+	}
+}
+
+// Settings represents the editable settings of a room.
+type Settings struct {
+	Id          int
+	Name        string
+	Description string
+	Door        DoorMode
+	// Password is only used when Door is DoorPassword.
+	Password           string
+	ShowOwnerName      bool
+	CanOthersMoveFurni bool
+	MaxVisitors        int
+}
+
+// Settings returns the editable settings of the room.
+// The password is not included in the room info and must be set before updating the settings.
+func (info Info) Settings() Settings {
+	return Settings{
+		Id:                 info.Id,
+		Name:               info.Name,
+		Description:        info.Description,
+		Door:               DoorMode(info.Door),
+		ShowOwnerName:      info.ShowOwnerName,
+		CanOthersMoveFurni: info.CanOthersMoveFurni,
+		MaxVisitors:        info.MaxVisitors,
+	}
+}
+
+// RequestInfo requests the information of the room with the specified ID.
+func (mgr *Manager) RequestInfo(id int) (*Info, error) {
+	mgr.ix.Send(out.GETFLATINFO, []byte(strconv.Itoa(id)))
+	pkt := mgr.ix.Recv(in.FLATINFO).If(flatInfoIdEq(id)).TimeoutSec(10).Wait()
+	if pkt == nil {
+		return nil, ErrTimeout
+	}
+	var info Info
+	pkt.Read(&info)
+	return &info, nil
+}
+
+func flatInfoIdEq(id int) func(p *g.Packet) bool {
+	return func(p *g.Packet) bool {
+		var info Info
+		p.ReadAt(0, &info)
+		return info.Id == id
+	}
+}
+
+// UpdateSettings updates the settings of the room with the specified ID.
+// The user must be the owner of the room.
+// Once the settings have been sent, the room info is requested again to confirm the update,
+// and the cached info is refreshed. Returns the updated info,
+// along with ErrNotUpdated if it does not reflect the new settings.
+func (mgr *Manager) UpdateSettings(settings Settings) (*Info, error) {
+	if settings.Door == DoorPassword && settings.Password == "" {
+		return nil, ErrPasswordRequired
+	}
+
+	// The name is sent with the door mode and owner name visibility.
+	mgr.ix.Send(out.UPDATEFLAT, []byte(strings.Join([]string{
+		strconv.Itoa(settings.Id),
+		settings.Name,
+		settings.Door.String(),
+		boolFlag(settings.ShowOwnerName),
+	}, "/")))
+
+	// The remaining settings are sent as key=value lines.
+	lines := []string{
+		"description=" + settings.Description,
+		"allsuperuser=" + boolFlag(settings.CanOthersMoveFurni),
+		"maxvisitors=" + strconv.Itoa(settings.MaxVisitors),
+	}
+	if settings.Door == DoorPassword {
+		lines = append(lines, "password="+settings.Password)
+	}
+	mgr.ix.Send(out.SETFLATINFO, []byte("/"+strconv.Itoa(settings.Id)+"/\r"+strings.Join(lines, "\r")))
+
+	// The server processes requests in order, so the info reflects the update once received.
+	mgr.invalidateInfo(settings.Id)
+	info, err := mgr.RequestInfo(settings.Id)
+	if err != nil {
+		return nil, err
+	}
+	mgr.refreshInfo(*info)
+
+	expected := settings
+	expected.Password = ""
+	if info.Settings() != expected {
+		return info, ErrNotUpdated
+	}
+	return info, nil
+}
+
+// Category requests the category ID of the room with the specified ID.
+func (mgr *Manager) Category(roomId int) (int, error) {
+	mgr.ix.Send(out.GETFLATCAT, roomId)
+	pkt := mgr.ix.Recv(in.FLATCAT).If(func(p *g.Packet) bool {
+		return p.ReadIntAt(0) == roomId
+	}).TimeoutSec(10).Wait()
+	if pkt == nil {
+		return 0, ErrTimeout
+	}
+	var id, categoryId int
+	pkt.Read(&id, &categoryId)
+	return categoryId, nil
+}
+
+// SetCategory sets the category of the room with the specified ID.
+// The user must be the owner of the room.
+// The category is requested again to confirm the change. Returns ErrNotUpdated if it was not applied.
+func (mgr *Manager) SetCategory(roomId, categoryId int) error {
+	mgr.ix.Send(out.SETFLATCAT, roomId, categoryId)
+	current, err := mgr.Category(roomId)
+	if err != nil {
+		return err
+	}
+	if current != categoryId {
+		return ErrNotUpdated
+	}
+	return nil
+}
+
+// DeleteRoom deletes the room with the specified ID.
+// The user must be the owner of the room.
+// The user's rooms are requested again to confirm the deletion.
+// Returns ErrNotUpdated if the room still exists.
+func (mgr *Manager) DeleteRoom(roomId int) error {
+	mgr.ix.Send(out.DELETEFLAT, []byte(strconv.Itoa(roomId)))
+	mgr.invalidateInfo(roomId)
+
+	mgr.ix.Send(out.SUSERF)
+	pkt := mgr.ix.Recv(in.FLAT_RESULTS, in.NOFLATSFORUSER).TimeoutSec(10).Block().Wait()
+	if pkt == nil {
+		return ErrTimeout
+	}
+	if !mgr.ix.Headers().Is(pkt.Header, in.FLAT_RESULTS) {
+		return nil
+	}
+	var rooms nav.Rooms
+	pkt.Read(&rooms)
+	for _, room := range rooms {
+		if room.Id == roomId {
+			return ErrNotUpdated
+		}
+	}
+	return nil
+}
+
+// refreshInfo updates the cached info of a room, and the current room's info if it is the same room.
+func (mgr *Manager) refreshInfo(info Info) {
+	mgr.updateCache(info)
+
+	mgr.mtxRoom.Lock()
+	defer mgr.mtxRoom.Unlock()
+	if mgr.isInRoom && mgr.roomId == info.Id {
+		mgr.roomInfo = &info
+	}
+}
+
+// invalidateInfo removes the room info with the specified ID from the cache.
+func (mgr *Manager) invalidateInfo(id int) {
+	mgr.mtxCache.Lock()
+	defer mgr.mtxCache.Unlock()
+	delete(mgr.infoCache, id)
+}
+
+func boolFlag(value bool) string {
+	if value {
+		return "1"
+	}
+	return "0"
+}
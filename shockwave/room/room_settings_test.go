@@ -0,0 +1,136 @@
+package room
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	g "xabbo.b7c.io/goearth"
+	"xabbo.b7c.io/goearth/internal/testix"
+	"xabbo.b7c.io/goearth/shockwave/in"
+	"xabbo.b7c.io/goearth/shockwave/out"
+)
+
+// testServer responds to room info, category and own room requests with its current state.
+type testServer struct {
+	mtx      sync.Mutex
+	info     Info
+	category int
+	rooms    string
+}
+
+// register registers the server's responders with the interceptor.
+func (srv *testServer) register(ix *testix.Interceptor) {
+	ix.Respond(out.GETFLATINFO, func(ix *testix.Interceptor, _ *g.Packet) {
+		srv.mtx.Lock()
+		defer srv.mtx.Unlock()
+		ix.DispatchLater(in.FLATINFO, srv.info)
+	})
+	ix.Respond(out.GETFLATCAT, func(ix *testix.Interceptor, _ *g.Packet) {
+		srv.mtx.Lock()
+		defer srv.mtx.Unlock()
+		ix.DispatchLater(in.FLATCAT, srv.info.Id, srv.category)
+	})
+	ix.Respond(out.SUSERF, func(ix *testix.Interceptor, _ *g.Packet) {
+		srv.mtx.Lock()
+		defer srv.mtx.Unlock()
+		if srv.rooms == "" {
+			ix.DispatchLater(in.NOFLATSFORUSER)
+		} else {
+			ix.DispatchLater(in.FLAT_RESULTS, srv.rooms)
+		}
+	})
+}
+
+func (srv *testServer) set(f func(srv *testServer)) {
+	srv.mtx.Lock()
+	defer srv.mtx.Unlock()
+	f(srv)
+}
+
+func newSettingsTest() (*testix.Interceptor, *Manager, *testServer) {
+	srv := &testServer{info: Info{Id: 123, Name: "Test room", Owner: "owner", MaxVisitors: 25}}
+	ix := testix.New(g.Shockwave)
+	ix.Resolve(out.UPDATEFLAT, out.SETFLATINFO, out.GETFLATINFO, out.GETFLATCAT, out.SETFLATCAT,
+		out.DELETEFLAT, out.SUSERF, in.FLATINFO, in.FLATCAT, in.FLAT_RESULTS, in.NOFLATSFORUSER)
+	srv.register(ix)
+	mgr := NewManager(ix)
+	mgr.LoadSnapshot(testSnapshot())
+	return ix, mgr, srv
+}
+
+func TestUpdateSettings(t *testing.T) {
+	ix, mgr, srv := newSettingsTest()
+	defer ix.Close()
+
+	settings := Settings{Id: 123, Name: "New name", Description: "desc", Door: DoorClosed, ShowOwnerName: true, MaxVisitors: 25}
+	srv.set(func(srv *testServer) {
+		srv.info.Name, srv.info.Description = settings.Name, settings.Description
+		srv.info.Door, srv.info.ShowOwnerName = int(DoorClosed), true
+	})
+
+	info, err := mgr.UpdateSettings(settings)
+	if err != nil {
+		t.Fatalf("failed to update settings: %v", err)
+	}
+	if info.Name != "New name" {
+		t.Fatalf("incorrect room name, expected: %q, actual: %q", "New name", info.Name)
+	}
+	if cur := mgr.Info(); cur == nil || cur.Name != "New name" {
+		t.Fatalf("expected current room info to be refreshed, actual: %+v", cur)
+	}
+	if cached := mgr.infoCache[123]; cached.Name != "New name" {
+		t.Fatalf("expected cached room info to be refreshed, actual: %+v", cached)
+	}
+
+	updateFlat := ix.SentTo(out.UPDATEFLAT)
+	if len(updateFlat) != 1 || string(updateFlat[0].Data) != "123/New name/closed/1" {
+		t.Fatalf("incorrect UPDATEFLAT packets: %v", updateFlat)
+	}
+	setFlatInfo := ix.SentTo(out.SETFLATINFO)
+	expected := "/123/\rdescription=desc\rallsuperuser=0\rmaxvisitors=25"
+	if len(setFlatInfo) != 1 || string(setFlatInfo[0].Data) != expected {
+		t.Fatalf("incorrect SETFLATINFO packets: %v", setFlatInfo)
+	}
+
+	settings.Name = "Rejected"
+	if _, err := mgr.UpdateSettings(settings); !errors.Is(err, ErrNotUpdated) {
+		t.Fatalf("incorrect error, expected: %v, actual: %v", ErrNotUpdated, err)
+	}
+
+	settings.Door = DoorPassword
+	if _, err := mgr.UpdateSettings(settings); !errors.Is(err, ErrPasswordRequired) {
+		t.Fatalf("incorrect error, expected: %v, actual: %v", ErrPasswordRequired, err)
+	}
+}
+
+func TestSetCategory(t *testing.T) {
+	ix, mgr, srv := newSettingsTest()
+	defer ix.Close()
+
+	srv.set(func(srv *testServer) { srv.category = 4 })
+	if err := mgr.SetCategory(123, 4); err != nil {
+		t.Fatalf("failed to set category: %v", err)
+	}
+	if err := mgr.SetCategory(123, 5); !errors.Is(err, ErrNotUpdated) {
+		t.Fatalf("incorrect error, expected: %v, actual: %v", ErrNotUpdated, err)
+	}
+}
+
+func TestDeleteRoom(t *testing.T) {
+	ix, mgr, srv := newSettingsTest()
+	defer ix.Close()
+
+	srv.set(func(srv *testServer) { srv.rooms = "123\tTest room\towner\topen\t\t0\t25\tnull\t\r" })
+	if err := mgr.DeleteRoom(123); !errors.Is(err, ErrNotUpdated) {
+		t.Fatalf("incorrect error, expected: %v, actual: %v", ErrNotUpdated, err)
+	}
+
+	srv.set(func(srv *testServer) { srv.rooms = "" })
+	if err := mgr.DeleteRoom(123); err != nil {
+		t.Fatalf("failed to delete room: %v", err)
+	}
+	if sent := ix.SentTo(out.DELETEFLAT); len(sent) != 2 || string(sent[0].Data) != "123" {
+		t.Fatalf("incorrect DELETEFLAT packets: %v", sent)
+	}
+}
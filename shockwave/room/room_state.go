@@ -0,0 +1,102 @@
+package room
+
+import (
+	"strconv"
+	"strings"
+)
+
+// DiceOff is the dice value of a dice that is turned off.
+const DiceOff = 0
+
+// onOffStates maps the states of on/off furni to their opposite state,
+// e.g. lamps use "ON"/"OFF" and gates use "O"/"C".
+var onOffStates = map[string]string{
+	"ON":    "OFF",
+	"OFF":   "ON",
+	"O":     "C",
+	"C":     "O",
+	"TRUE":  "FALSE",
+	"FALSE": "TRUE",
+}
+
+// teleporterClasses holds the classes of teleporters.
+var teleporterClasses = map[string]struct{}{
+	"door":          {},
+	"doorB":         {},
+	"doorC":         {},
+	"doorD":         {},
+	"teleport_door": {},
+}
+
+// IsTeleporter returns whether the object is a teleporter.
+func (obj Object) IsTeleporter() bool {
+	_, ok := teleporterClasses[obj.Class]
+	return ok
+}
+
+// IsDice returns whether the object is a dice.
+func (obj Object) IsDice() bool {
+	return strings.HasPrefix(obj.Class, "edice")
+}
+
+// DiceValue returns the value of a dice, and whether the object is a dice with a known value.
+// A value of DiceOff indicates that the dice is turned off.
+func (obj Object) DiceValue() (int, bool) {
+	if !obj.IsDice() {
+		return 0, false
+	}
+	value, err := strconv.Atoi(obj.StuffData)
+	if err != nil || value < 0 || value > 6 {
+		return 0, false
+	}
+	return value, true
+}
+
+// IsOn returns whether an on/off object is turned on or open,
+// and whether the object's state could be interpreted as on/off.
+func (obj Object) IsOn() (on, ok bool) {
+	switch strings.ToUpper(obj.StuffData) {
+	case "ON", "O", "TRUE":
+		return true, true
+	case "OFF", "C", "FALSE":
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+// ToggledState returns the opposite state of an on/off object,
+// and whether the object's state could be interpreted as on/off.
+// Teleporters are not toggled by setting their state, so ok is false for teleporters.
+func (obj Object) ToggledState() (string, bool) {
+	if obj.IsTeleporter() {
+		return "", false
+	}
+	state, ok := onOffStates[strings.ToUpper(obj.StuffData)]
+	if ok && strings.ToLower(obj.StuffData) == obj.StuffData {
+		state = strings.ToLower(state)
+	}
+	return state, ok
+}
+
+// parseDiceValue parses the contents of a DICE_VALUE packet in the format "id" while the dice is rolling,
+// or "id value" once it has landed, where value is the object ID multiplied by 38 plus the dice value.
+func parseDiceValue(s string) (id, value int, rolling bool, ok bool) {
+	strId, strValue, landed := strings.Cut(strings.TrimSpace(s), " ")
+	id, err := strconv.Atoi(strId)
+	if err != nil {
+		return
+	}
+	if !landed {
+		return id, 0, true, true
+	}
+	n, err := strconv.Atoi(strValue)
+	if err != nil {
+		return
+	}
+	value = n - id*38
+	if value < DiceOff || value > 6 {
+		return 0, 0, false, false
+	}
+	return id, value, false, true
+}
@@ -0,0 +1,55 @@
+package room
+
+import "testing"
+
+func TestParseDiceValue(t *testing.T) {
+	if id, _, rolling, ok := parseDiceValue("1234"); !ok || !rolling || id != 1234 {
+		t.Errorf("incorrect rolling dice, expected: id 1234 rolling, actual: id %d rolling %t", id, rolling)
+	}
+	if id, value, rolling, ok := parseDiceValue("1234 46897"); !ok || rolling || id != 1234 || value != 5 {
+		t.Errorf("incorrect dice value, expected: 5, actual: %d", value)
+	}
+	if id, value, rolling, ok := parseDiceValue("1234 46892"); !ok || rolling || id != 1234 || value != DiceOff {
+		t.Errorf("incorrect dice value, expected: %d, actual: %d", DiceOff, value)
+	}
+	if _, _, _, ok := parseDiceValue("abc"); ok {
+		t.Errorf("expected invalid dice value")
+	}
+	if _, _, _, ok := parseDiceValue("1234 1239"); ok {
+		t.Errorf("expected out of range dice value to be invalid")
+	}
+}
+
+func TestObjectState(t *testing.T) {
+	dice := Object{Class: "edicehc", StuffData: "4"}
+	if value, ok := dice.DiceValue(); !ok || value != 4 {
+		t.Errorf("incorrect dice value, expected: 4, actual: %d", value)
+	}
+
+	tests := []struct {
+		data    string
+		on      bool
+		toggled string
+	}{
+		{"ON", true, "OFF"},
+		{"C", false, "O"},
+		{"false", false, "true"},
+	}
+	for _, test := range tests {
+		obj := Object{Class: "lamp", StuffData: test.data}
+		if on, ok := obj.IsOn(); !ok || on != test.on {
+			t.Errorf("incorrect state for %q, expected: %t, actual: %t", test.data, test.on, on)
+		}
+		if toggled, ok := obj.ToggledState(); !ok || toggled != test.toggled {
+			t.Errorf("incorrect toggled state for %q, expected: %q, actual: %q", test.data, test.toggled, toggled)
+		}
+	}
+
+	if _, ok := (Object{Class: "doorB", StuffData: "TRUE"}).ToggledState(); ok {
+		t.Errorf("expected teleporters not to be toggled")
+	}
+
+	if _, ok := (Object{StuffData: "3"}).IsOn(); ok {
+		t.Errorf("expected unknown on/off state")
+	}
+}
@@ -0,0 +1,216 @@
+package room
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Posture represents the posture of an entity.
+type Posture int
+
+const (
+	Stand Posture = iota
+	Sit
+	Lay
+)
+
+func (posture Posture) String() string {
+	switch posture {
+	case Sit:
+		return "sit"
+	case Lay:
+		return "lay"
+	default:
+		return "stand"
+	}
+}
+
+// Gesture represents a facial expression shown by an entity.
+type Gesture string
+
+const (
+	GestureSmile     Gesture = "sml"
+	GestureAngry     Gesture = "agr"
+	GestureSad       Gesture = "sad"
+	GestureSurprised Gesture = "srp"
+	GestureSpeak     Gesture = "spk"
+)
+
+// Statuses holds the statuses of an entity parsed from its action string,
+// e.g. "/mv 3,4,0.0/sit 1.0/carryd Tea/".
+type Statuses struct {
+	// Moving indicates whether the entity is moving to the Destination tile.
+	Moving      bool
+	Destination Tile
+	// Posture is the posture of the entity, and Height the height it is sitting or lying at.
+	Posture Posture
+	Height  float64
+	// Carrying is the name of the item being carried, and Using indicates
+	// whether the item is being consumed, e.g. drinking or eating.
+	Carrying string
+	Using    bool
+	// Dancing indicates whether the entity is dancing with the specified Dance style.
+	Dancing bool
+	Dance   Dance
+	Waving  bool
+	// Signing indicates whether the entity is holding up the specified Sign.
+	Signing bool
+	Sign    Sign
+	Talking bool
+	// FlatControl indicates whether the entity has rights in the room,
+	// and FlatControlValue holds the value of the status, which is "useradmin" for the room owner.
+	FlatControl      bool
+	FlatControlValue string
+	Swimming         bool
+	Trading          bool
+	Gesture          Gesture
+	// Other holds any unrecognized statuses mapped by name.
+	Other map[string]string
+}
+
+// ParseStatuses parses the statuses from the specified action string.
+func ParseStatuses(action string) (statuses Statuses) {
+	for _, status := range strings.Split(action, "/") {
+		if status == "" {
+			continue
+		}
+		name, value, _ := strings.Cut(status, " ")
+		switch name {
+		case "mv":
+			if tile, ok := parseTile(value); ok {
+				statuses.Moving = true
+				statuses.Destination = tile
+			}
+		case "sit", "lay":
+			statuses.Posture = Sit
+			if name == "lay" {
+				statuses.Posture = Lay
+			}
+			height, _, _ := strings.Cut(value, " ")
+			statuses.Height, _ = strconv.ParseFloat(height, 64)
+		case "carryd", "carryf":
+			statuses.Carrying = value
+		case "drink", "eat":
+			statuses.Carrying = value
+			statuses.Using = true
+		case "dance":
+			statuses.Dancing = true
+			if n, err := strconv.Atoi(value); err == nil {
+				statuses.Dance = Dance(n)
+			}
+		case "wave":
+			statuses.Waving = true
+		case "sign":
+			statuses.Signing = true
+			if n, err := strconv.Atoi(value); err == nil {
+				statuses.Sign = Sign(n)
+			}
+		case "talk":
+			statuses.Talking = true
+		case "flatctrl":
+			statuses.FlatControl = true
+			statuses.FlatControlValue = value
+		case "swim":
+			statuses.Swimming = true
+		case "trd":
+			statuses.Trading = true
+		case "gest":
+			statuses.Gesture = Gesture(value)
+		default:
+			if statuses.Other == nil {
+				statuses.Other = map[string]string{}
+			}
+			statuses.Other[name] = value
+		}
+	}
+	return
+}
+
+// parseTile parses a tile in the format "x,y,z".
+func parseTile(s string) (tile Tile, ok bool) {
+	fields := strings.Split(s, ",")
+	if len(fields) != 3 {
+		return
+	}
+	var err error
+	if tile.X, err = strconv.Atoi(fields[0]); err != nil {
+		return
+	}
+	if tile.Y, err = strconv.Atoi(fields[1]); err != nil {
+		return
+	}
+	if tile.Z, err = strconv.ParseFloat(fields[2], 64); err != nil {
+		return
+	}
+	return tile, true
+}
+
+// Statuses parses the statuses from the status update's action string.
+func (status EntityStatus) Statuses() Statuses {
+	return ParseStatuses(status.Action)
+}
+
+// IsMoving returns whether the entity is moving.
+func (ent Entity) IsMoving() bool {
+	return ent.Statuses.Moving
+}
+
+// Destination returns the tile the entity is moving to, and whether the entity is moving.
+func (ent Entity) Destination() (Tile, bool) {
+	return ent.Statuses.Destination, ent.Statuses.Moving
+}
+
+// Sitting returns the height the entity is sitting at, and whether the entity is sitting.
+func (ent Entity) Sitting() (float64, bool) {
+	return ent.Statuses.Height, ent.Statuses.Posture == Sit
+}
+
+// Lying returns the height the entity is lying at, and whether the entity is lying.
+func (ent Entity) Lying() (float64, bool) {
+	return ent.Statuses.Height, ent.Statuses.Posture == Lay
+}
+
+// Carrying returns the name of the item the entity is carrying, and whether the entity is carrying an item.
+func (ent Entity) Carrying() (string, bool) {
+	return ent.Statuses.Carrying, ent.Statuses.Carrying != ""
+}
+
+// Dancing returns the dance style of the entity, and whether the entity is dancing.
+func (ent Entity) Dancing() (Dance, bool) {
+	return ent.Statuses.Dance, ent.Statuses.Dancing
+}
+
+// IsWaving returns whether the entity is waving.
+func (ent Entity) IsWaving() bool {
+	return ent.Statuses.Waving
+}
+
+// Sign returns the sign the entity is holding up, and whether the entity is holding up a sign.
+func (ent Entity) Sign() (Sign, bool) {
+	return ent.Statuses.Sign, ent.Statuses.Signing
+}
+
+// IsTalking returns whether the entity is talking.
+func (ent Entity) IsTalking() bool {
+	return ent.Statuses.Talking
+}
+
+// HasRights returns whether the entity has rights in the room.
+func (ent Entity) HasRights() bool {
+	return ent.Statuses.FlatControl
+}
+
+// IsOwner returns whether the entity is the owner of the room.
+func (ent Entity) IsOwner() bool {
+	return ent.Statuses.FlatControlValue == "useradmin"
+}
+
+// IsSwimming returns whether the entity is swimming.
+func (ent Entity) IsSwimming() bool {
+	return ent.Statuses.Swimming
+}
+
+// Gesture returns the gesture the entity is showing, and whether the entity is showing a gesture.
+func (ent Entity) Gesture() (Gesture, bool) {
+	return ent.Statuses.Gesture, ent.Statuses.Gesture != ""
+}
@@ -0,0 +1,45 @@
+package room
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseStatuses(t *testing.T) {
+	tests := []struct {
+		action   string
+		expected Statuses
+	}{
+		{"", Statuses{}},
+		{"/mv 3,4,0.0/", Statuses{Moving: true, Destination: Tile{3, 4, 0}}},
+		{"/sit 1.0/carryd Tea/", Statuses{Posture: Sit, Height: 1, Carrying: "Tea"}},
+		{"/lay 0.5 null/drink Coffee/", Statuses{Posture: Lay, Height: 0.5, Carrying: "Coffee", Using: true}},
+		{"/flatctrl useradmin/dance 2/", Statuses{FlatControl: true, FlatControlValue: "useradmin", Dancing: true, Dance: DanceDuckFunk}},
+		{"/dance/wave/talk/", Statuses{Dancing: true, Waving: true, Talking: true}},
+		{"/sign 7/gest sml/swim/trd/", Statuses{Signing: true, Sign: 7, Gesture: GestureSmile, Swimming: true, Trading: true}},
+		{"/sleep/", Statuses{Other: map[string]string{"sleep": ""}}},
+	}
+
+	for _, test := range tests {
+		actual := ParseStatuses(test.action)
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("incorrect statuses for %q, expected: %+v, actual: %+v", test.action, test.expected, actual)
+		}
+	}
+}
+
+func TestEntityStatuses(t *testing.T) {
+	ent := Entity{Statuses: ParseStatuses("/flatctrl useradmin/mv 1,2,0.0/sit 1.0/")}
+	if dst, ok := ent.Destination(); !ok || dst != (Tile{1, 2, 0}) {
+		t.Errorf("incorrect destination, expected: %v, actual: %v", Tile{1, 2, 0}, dst)
+	}
+	if height, ok := ent.Sitting(); !ok || height != 1 {
+		t.Errorf("incorrect sitting height, expected: %v, actual: %v", 1.0, height)
+	}
+	if !ent.HasRights() || !ent.IsOwner() {
+		t.Errorf("expected entity to be the room owner")
+	}
+	if _, ok := ent.Dancing(); ok {
+		t.Errorf("expected entity not to be dancing")
+	}
+}
@@ -0,0 +1,66 @@
+package room
+
+import (
+	"reflect"
+	"testing"
+
+	g "xabbo.b7c.io/goearth"
+)
+
+func TestPassiveObjects(t *testing.T) {
+	wire := "J" +
+		"a1\x02chair_basic\x02" + "K" + "PA" + "1.5\x02" + "J" +
+		"b2\x02plant\x02" + "QA" + "RA" + "0\x02" + "H"
+	expected := []PassiveObject{
+		{Id: "a1", Class: "chair_basic", X: 3, Y: 4, Z: 1.5, Direction: 2},
+		{Id: "b2", Class: "plant", X: 5, Y: 6, Z: 0, Direction: 0},
+	}
+
+	pkt := &g.Packet{Client: g.Shockwave, Header: g.Header{Dir: g.In}, Data: []byte(wire)}
+	var actual []PassiveObject
+	pkt.Read(&actual)
+	if pkt.Pos != pkt.Length() {
+		t.Fatalf("packet was not fully read, position: %d, length: %d", pkt.Pos, pkt.Length())
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("incorrect passive objects, expected: %+v, actual: %+v", expected, actual)
+	}
+
+	pkt = &g.Packet{Client: g.Shockwave, Header: g.Header{Dir: g.In}}
+	pkt.Write(g.Length(len(expected)), expected[0], expected[1])
+	if string(pkt.Data) != wire {
+		t.Fatalf("incorrect packet data, expected: %q, actual: %q", wire, pkt.Data)
+	}
+}
+
+func TestItems(t *testing.T) {
+	pkt := &g.Packet{Client: g.Shockwave, Header: g.Header{Dir: g.In}}
+	pkt.WriteString("10\tposter\towner\t:w=3,0 l=12,45 r\t1\r")
+	pkt.WriteString("11\tposter\towner\t:w=3,0  l=12,45   r\t1\r")
+	pkt.WriteString("12\tpost.it\towner\tfrontwall 3.5,2.0\t1\r")
+	pkt.Pos = 0
+
+	var items Items
+	pkt.Read(&items)
+	if len(items) != 3 {
+		t.Fatalf("incorrect number of items, expected: %d, actual: %d", 3, len(items))
+	}
+
+	expected := WallLocation{WallX: 3, WallY: 0, X: 12, Y: 45, Orientation: WallRight}
+	for _, item := range items[:2] {
+		if !item.ValidLocation || item.Location != expected {
+			t.Errorf("incorrect location for item %d, expected: %+v, actual: %+v", item.Id, expected, item.Location)
+		}
+	}
+
+	invalid := items[2]
+	if invalid.ValidLocation {
+		t.Errorf("expected location of item %d to be invalid", invalid.Id)
+	}
+	if invalid.RawLocation != "frontwall 3.5,2.0" {
+		t.Errorf("incorrect raw location, expected: %q, actual: %q", "frontwall 3.5,2.0", invalid.RawLocation)
+	}
+	if invalid.Id != 12 || invalid.Class != "post.it" {
+		t.Errorf("incorrect item: %+v", invalid)
+	}
+}
@@ -0,0 +1,202 @@
+package room
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// Snapshot represents the state of a room at a point in time.
+// It can be encoded to and decoded from JSON.
+type Snapshot struct {
+	Id             int             `json:"id"`
+	Model          string          `json:"model"`
+	Info           *Info           `json:"info,omitempty"`
+	Heightmap      *Heightmap      `json:"heightmap,omitempty"`
+	Objects        []Object        `json:"objects"`
+	PassiveObjects []PassiveObject `json:"passiveObjects,omitempty"`
+	Items          []Item          `json:"items"`
+	Entities       []Entity        `json:"entities"`
+}
+
+// Snapshot returns a snapshot of the current room state.
+// Objects and items are ordered by ID, and entities by index.
+func (mgr *Manager) Snapshot() *Snapshot {
+	snapshot := &Snapshot{}
+
+	mgr.mtxRoom.RLock()
+	snapshot.Id = mgr.roomId
+	snapshot.Model = mgr.roomModel
+	if mgr.roomInfo != nil {
+		info := *mgr.roomInfo
+		snapshot.Info = &info
+	}
+	if mgr.heightmap != nil {
+		snapshot.Heightmap = mgr.heightmap.Clone()
+	}
+	mgr.mtxRoom.RUnlock()
+
+	mgr.mtxObjs.RLock()
+	snapshot.Objects = make([]Object, 0, len(mgr.objects))
+	for _, obj := range mgr.objects {
+		snapshot.Objects = append(snapshot.Objects, obj)
+	}
+	snapshot.PassiveObjects = slices.Clone(mgr.passive)
+	mgr.mtxObjs.RUnlock()
+	slices.SortFunc(snapshot.Objects, func(a, b Object) int { return cmp.Compare(a.Id, b.Id) })
+
+	mgr.mtxItems.RLock()
+	snapshot.Items = make([]Item, 0, len(mgr.items))
+	for _, item := range mgr.items {
+		snapshot.Items = append(snapshot.Items, item)
+	}
+	mgr.mtxItems.RUnlock()
+	slices.SortFunc(snapshot.Items, func(a, b Item) int { return cmp.Compare(a.Id, b.Id) })
+
+	mgr.mtxEnts.RLock()
+	snapshot.Entities = make([]Entity, 0, len(mgr.entities))
+	for _, ent := range mgr.entities {
+		snapshot.Entities = append(snapshot.Entities, ent)
+	}
+	mgr.mtxEnts.RUnlock()
+	slices.SortFunc(snapshot.Entities, func(a, b Entity) int { return cmp.Compare(a.Index, b.Index) })
+
+	return snapshot
+}
+
+// FromSnapshot creates a manager with its state loaded from the specified snapshot,
+// for offline analysis and testing. The manager is not attached to an interceptor,
+// so it does not receive updates and its actions cannot be used.
+func FromSnapshot(snapshot *Snapshot) *Manager {
+	mgr := newManager(nil)
+	mgr.LoadSnapshot(snapshot)
+	return mgr
+}
+
+// LoadSnapshot replaces the current room state with the state from the specified snapshot.
+// No events are dispatched.
+func (mgr *Manager) LoadSnapshot(snapshot *Snapshot) {
+	mgr.mtxRoom.Lock()
+	mgr.isInRoom = true
+	mgr.roomId = snapshot.Id
+	mgr.roomModel = snapshot.Model
+	mgr.roomInfo = nil
+	if snapshot.Info != nil {
+		info := *snapshot.Info
+		mgr.roomInfo = &info
+	}
+	mgr.heightmap = nil
+	if snapshot.Heightmap != nil {
+		mgr.heightmap = snapshot.Heightmap.Clone()
+	}
+	mgr.mtxRoom.Unlock()
+
+	mgr.mtxObjs.Lock()
+	mgr.objects = make(map[int]Object, len(snapshot.Objects))
+	for _, obj := range snapshot.Objects {
+		mgr.objects[obj.Id] = obj
+	}
+	mgr.passive = slices.Clone(snapshot.PassiveObjects)
+	mgr.mtxObjs.Unlock()
+
+	mgr.mtxItems.Lock()
+	mgr.items = make(map[int]Item, len(snapshot.Items))
+	for _, item := range snapshot.Items {
+		mgr.items[item.Id] = item
+	}
+	mgr.mtxItems.Unlock()
+
+	mgr.mtxEnts.Lock()
+	mgr.entities = make(map[int]Entity, len(snapshot.Entities))
+	mgr.names = make(map[string]int, len(snapshot.Entities))
+	mgr.figures = map[string][]int{}
+	for _, ent := range snapshot.Entities {
+		mgr.entities[ent.Index] = ent
+		mgr.indexEntity(ent)
+	}
+	mgr.mtxEnts.Unlock()
+
+	dbg.Printf("loaded snapshot of room %d", snapshot.Id)
+}
+
+// SnapshotDiff holds the differences between two room snapshots.
+type SnapshotDiff struct {
+	ObjectsAdded    []Object
+	ObjectsRemoved  []Object
+	ObjectsMoved    []ObjectUpdateArgs
+	ItemsAdded      []Item
+	ItemsRemoved    []Item
+	ItemsMoved      []ItemUpdateArgs
+	EntitiesAdded   []Entity
+	EntitiesRemoved []Entity
+	EntitiesMoved   []EntityUpdateArgs
+}
+
+// Empty returns whether there are no differences.
+func (diff *SnapshotDiff) Empty() bool {
+	return len(diff.ObjectsAdded) == 0 && len(diff.ObjectsRemoved) == 0 && len(diff.ObjectsMoved) == 0 &&
+		len(diff.ItemsAdded) == 0 && len(diff.ItemsRemoved) == 0 && len(diff.ItemsMoved) == 0 &&
+		len(diff.EntitiesAdded) == 0 && len(diff.EntitiesRemoved) == 0 && len(diff.EntitiesMoved) == 0
+}
+
+// Diff returns the floor items, wall items and entities that were added, removed or moved between snapshots a and b.
+// Objects and items are matched by ID. As the server reuses entity indexes,
+// users are matched by name, and other entities by index and name.
+func Diff(a, b *Snapshot) SnapshotDiff {
+	var diff SnapshotDiff
+
+	diff.ObjectsAdded, diff.ObjectsRemoved, diff.ObjectsMoved = diffSlices(a.Objects, b.Objects,
+		func(obj Object) int { return obj.Id },
+		func(pre, cur Object) bool {
+			return pre.X != cur.X || pre.Y != cur.Y || pre.Z != cur.Z || pre.Direction != cur.Direction
+		},
+		func(pre, cur Object) ObjectUpdateArgs { return ObjectUpdateArgs{Pre: pre, Object: cur} })
+
+	diff.ItemsAdded, diff.ItemsRemoved, diff.ItemsMoved = diffSlices(a.Items, b.Items,
+		func(item Item) int { return item.Id },
+		func(pre, cur Item) bool { return pre.RawLocation != cur.RawLocation },
+		func(pre, cur Item) ItemUpdateArgs { return ItemUpdateArgs{Pre: pre, Item: cur} })
+
+	diff.EntitiesAdded, diff.EntitiesRemoved, diff.EntitiesMoved = diffSlices(a.Entities, b.Entities,
+		diffEntityKey,
+		func(pre, cur Entity) bool { return pre.Tile != cur.Tile },
+		func(pre, cur Entity) EntityUpdateArgs { return EntityUpdateArgs{Pre: pre, Entity: cur} })
+
+	return diff
+}
+
+// entityKey identifies an entity across snapshots.
+type entityKey struct {
+	name  string
+	index int
+}
+
+func diffEntityKey(ent Entity) entityKey {
+	if ent.Type == User {
+		return entityKey{name: strings.ToLower(ent.Name), index: -1}
+	}
+	return entityKey{name: ent.Name, index: ent.Index}
+}
+
+func diffSlices[T, U any, K comparable](a, b []T, key func(T) K, moved func(pre, cur T) bool, update func(pre, cur T) U) (added, removed []T, updates []U) {
+	pre := make(map[K]T, len(a))
+	for _, v := range a {
+		pre[key(v)] = v
+	}
+	cur := make(map[K]bool, len(b))
+	for _, v := range b {
+		k := key(v)
+		cur[k] = true
+		if p, ok := pre[k]; !ok {
+			added = append(added, v)
+		} else if moved(p, v) {
+			updates = append(updates, update(p, v))
+		}
+	}
+	for _, v := range a {
+		if !cur[key(v)] {
+			removed = append(removed, v)
+		}
+	}
+	return
+}
@@ -0,0 +1,114 @@
+package room
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func testSnapshot() *Snapshot {
+	return &Snapshot{
+		Id:        123,
+		Model:     "model_a",
+		Info:      &Info{Id: 123, Name: "Test room", Owner: "owner"},
+		Heightmap: ParseHeightmap("xx00\r0000\r0010"),
+		Objects: []Object{
+			{Id: 1, Class: "chair", X: 1, Y: 1, Width: 1, Height: 1, Direction: 2},
+			{Id: 2, Class: "table", X: 2, Y: 1, Width: 2, Height: 2},
+		},
+		Items: []Item{
+			{
+				Id: 10, Class: "poster", Location: WallLocation{WallX: 3, WallY: 0, X: 12, Y: 45, Orientation: WallRight},
+				RawLocation: ":w=3,0 l=12,45 r", ValidLocation: true,
+			},
+		},
+		Entities: []Entity{
+			{EntityBase: EntityBase{Index: 0, Name: "user", Type: User, Tile: Tile{1, 2, 0}}},
+		},
+	}
+}
+
+func TestSnapshotJSON(t *testing.T) {
+	expected := testSnapshot()
+
+	data, err := json.Marshal(expected)
+	if err != nil {
+		t.Fatalf("failed to encode snapshot: %v", err)
+	}
+
+	var actual Snapshot
+	if err := json.Unmarshal(data, &actual); err != nil {
+		t.Fatalf("failed to decode snapshot: %v", err)
+	}
+	if !reflect.DeepEqual(&actual, expected) {
+		t.Fatalf("incorrect snapshot, expected: %+v, actual: %+v", expected, &actual)
+	}
+}
+
+func TestFromSnapshot(t *testing.T) {
+	expected := testSnapshot()
+	mgr := FromSnapshot(expected)
+
+	if !mgr.IsInRoom() || mgr.Id() != expected.Id {
+		t.Fatalf("incorrect room ID, expected: %d, actual: %d", expected.Id, mgr.Id())
+	}
+	if mgr.ObjectCount() != 2 || mgr.Object(2) == nil {
+		t.Fatalf("incorrect object count, expected: %d, actual: %d", 2, mgr.ObjectCount())
+	}
+
+	actual := mgr.Snapshot()
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("incorrect snapshot, expected: %+v, actual: %+v", expected, actual)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a, b := testSnapshot(), testSnapshot()
+	b.Objects = []Object{
+		{Id: 2, Class: "table", X: 3, Y: 1, Width: 2, Height: 2},
+		{Id: 3, Class: "plant", X: 0, Y: 3, Width: 1, Height: 1},
+	}
+	b.Entities[0].Tile = Tile{2, 2, 0}
+
+	diff := Diff(a, b)
+	if len(diff.ObjectsAdded) != 1 || diff.ObjectsAdded[0].Id != 3 {
+		t.Errorf("incorrect added objects: %+v", diff.ObjectsAdded)
+	}
+	if len(diff.ObjectsRemoved) != 1 || diff.ObjectsRemoved[0].Id != 1 {
+		t.Errorf("incorrect removed objects: %+v", diff.ObjectsRemoved)
+	}
+	if len(diff.ObjectsMoved) != 1 || diff.ObjectsMoved[0].Object.X != 3 {
+		t.Errorf("incorrect moved objects: %+v", diff.ObjectsMoved)
+	}
+	if len(diff.EntitiesMoved) != 1 || diff.EntitiesMoved[0].Pre.Tile != (Tile{1, 2, 0}) {
+		t.Errorf("incorrect moved entities: %+v", diff.EntitiesMoved)
+	}
+	if len(diff.ItemsAdded) != 0 || len(diff.ItemsRemoved) != 0 || len(diff.ItemsMoved) != 0 {
+		t.Errorf("unexpected item changes: %+v", diff)
+	}
+
+	if diff := Diff(a, a); !diff.Empty() {
+		t.Errorf("expected empty diff, actual: %+v", diff)
+	}
+}
+
+func TestDiffEntityIndexReuse(t *testing.T) {
+	a, b := testSnapshot(), testSnapshot()
+	// "user" left and "other" entered with the same index, while "User2" re-entered with a new index.
+	a.Entities = append(a.Entities, Entity{EntityBase: EntityBase{Index: 1, Name: "User2", Type: User, Tile: Tile{0, 1, 0}}})
+	b.Entities = []Entity{
+		{EntityBase: EntityBase{Index: 0, Name: "other", Type: User, Tile: Tile{1, 2, 0}}},
+		{EntityBase: EntityBase{Index: 2, Name: "user2", Type: User, Tile: Tile{0, 2, 0}}},
+	}
+
+	diff := Diff(a, b)
+	if len(diff.EntitiesAdded) != 1 || diff.EntitiesAdded[0].Name != "other" {
+		t.Errorf("incorrect added entities: %+v", diff.EntitiesAdded)
+	}
+	if len(diff.EntitiesRemoved) != 1 || diff.EntitiesRemoved[0].Name != "user" {
+		t.Errorf("incorrect removed entities: %+v", diff.EntitiesRemoved)
+	}
+	if len(diff.EntitiesMoved) != 1 || diff.EntitiesMoved[0].Entity.Index != 2 {
+		t.Errorf("incorrect moved entities: %+v", diff.EntitiesMoved)
+	}
+}
@@ -0,0 +1,104 @@
+package room
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	g "xabbo.b7c.io/goearth"
+)
+
+// WallOrientation represents the wall that a wall item is placed on.
+type WallOrientation byte
+
+const (
+	WallLeft  WallOrientation = 'l'
+	WallRight WallOrientation = 'r'
+)
+
+func (o WallOrientation) String() string {
+	return string(o)
+}
+
+// WallLocation represents the location of a wall item,
+// in the format ":w=WallX,WallY l=X,Y Orientation", e.g. ":w=3,2 l=12,45 r".
+type WallLocation struct {
+	// WallX and WallY are the coordinates of the wall tile.
+	WallX, WallY int
+	// X and Y are the local pixel coordinates on the wall tile.
+	X, Y        int
+	Orientation WallOrientation
+}
+
+// ParseWallLocation parses a wall location from the specified string.
+func ParseWallLocation(s string) (loc WallLocation, err error) {
+	fields := strings.Fields(s)
+	if len(fields) != 3 {
+		return loc, fmt.Errorf("invalid wall location: %q", s)
+	}
+	wall, ok := strings.CutPrefix(fields[0], ":w=")
+	if !ok {
+		return loc, fmt.Errorf("invalid wall location: %q", s)
+	}
+	if loc.WallX, loc.WallY, err = parseWallPair(wall); err != nil {
+		return loc, fmt.Errorf("invalid wall location: %q", s)
+	}
+	local, ok := strings.CutPrefix(fields[1], "l=")
+	if !ok {
+		return loc, fmt.Errorf("invalid wall location: %q", s)
+	}
+	if loc.X, loc.Y, err = parseWallPair(local); err != nil {
+		return loc, fmt.Errorf("invalid wall location: %q", s)
+	}
+	switch fields[2] {
+	case "l":
+		loc.Orientation = WallLeft
+	case "r":
+		loc.Orientation = WallRight
+	default:
+		return loc, fmt.Errorf("invalid wall orientation: %q", fields[2])
+	}
+	return loc, nil
+}
+
+func parseWallPair(s string) (a, b int, err error) {
+	strA, strB, ok := strings.Cut(s, ",")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid coordinate pair: %q", s)
+	}
+	if a, err = strconv.Atoi(strA); err != nil {
+		return
+	}
+	b, err = strconv.Atoi(strB)
+	return
+}
+
+func (loc *WallLocation) Parse(p *g.Packet, pos *int) {
+	s := p.ReadStringPtr(pos)
+	var err error
+	if *loc, err = ParseWallLocation(s); err != nil {
+		dbg.Printf("WARNING: %s", err)
+	}
+}
+
+func (loc WallLocation) Compose(p *g.Packet, pos *int) {
+	p.WriteStringPtr(pos, loc.String())
+}
+
+// String formats the wall location as a string, e.g. ":w=3,2 l=12,45 r".
+func (loc WallLocation) String() string {
+	return ":w=" + strconv.Itoa(loc.WallX) + "," + strconv.Itoa(loc.WallY) +
+		" l=" + strconv.Itoa(loc.X) + "," + strconv.Itoa(loc.Y) +
+		" " + loc.Orientation.String()
+}
+
+// MarshalText encodes the wall location as its string representation.
+func (loc WallLocation) MarshalText() ([]byte, error) {
+	return []byte(loc.String()), nil
+}
+
+// UnmarshalText decodes the wall location from its string representation.
+func (loc *WallLocation) UnmarshalText(text []byte) (err error) {
+	*loc, err = ParseWallLocation(string(text))
+	return
+}
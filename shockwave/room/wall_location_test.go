@@ -0,0 +1,46 @@
+package room
+
+import (
+	"testing"
+
+	g "xabbo.b7c.io/goearth"
+)
+
+func TestWallLocation(t *testing.T) {
+	s := ":w=3,2 l=12,45 r"
+	expected := WallLocation{WallX: 3, WallY: 2, X: 12, Y: 45, Orientation: WallRight}
+
+	loc, err := ParseWallLocation(s)
+	if err != nil {
+		t.Fatalf("failed to parse wall location: %v", err)
+	}
+	if loc != expected {
+		t.Fatalf("incorrect wall location, expected: %+v, actual: %+v", expected, loc)
+	}
+	if loc.String() != s {
+		t.Fatalf("incorrect wall location string, expected: %q, actual: %q", s, loc.String())
+	}
+
+	for _, invalid := range []string{"", ":w=3,2 l=12,45", ":w=3 l=12,45 r", "w=3,2 l=12,45 l", ":w=3,2 l=12,45 x"} {
+		if _, err := ParseWallLocation(invalid); err == nil {
+			t.Errorf("expected error parsing %q", invalid)
+		}
+	}
+}
+
+func TestWallLocationPacket(t *testing.T) {
+	expected := WallLocation{WallX: 1, WallY: 7, X: 30, Y: 2, Orientation: WallLeft}
+
+	pkt := &g.Packet{Client: g.Shockwave, Header: g.Header{Dir: g.In}}
+	pkt.Write(expected)
+	pkt.Pos = 0
+
+	var actual WallLocation
+	pkt.Read(&actual)
+	if pkt.Pos != pkt.Length() {
+		t.Fatalf("packet was not fully read, position: %d, length: %d", pkt.Pos, pkt.Length())
+	}
+	if actual != expected {
+		t.Fatalf("incorrect wall location, expected: %+v, actual: %+v", expected, actual)
+	}
+}
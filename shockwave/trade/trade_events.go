@@ -16,21 +16,25 @@ type AcceptArgs struct {
 
 // Updated registers an event handler that is invoked when the trade is updated.
 // If a trade was opened, the Opened flag on the event arguments will be set.
-func (mgr *Manager) Updated(handler g.EventHandler[Args]) {
-	mgr.updated.Register(handler)
+func (mgr *Manager) Updated(handlers ...g.EventHandler[Args]) *g.Event[Args] {
+	mgr.updated.Register(handlers...)
+	return &mgr.updated
 }
 
 // Accepted registers an event handler that is invoked when the trade is accepted.
-func (mgr *Manager) Accepted(handler g.EventHandler[AcceptArgs]) {
-	mgr.accepted.Register(handler)
+func (mgr *Manager) Accepted(handlers ...g.EventHandler[AcceptArgs]) *g.Event[AcceptArgs] {
+	mgr.accepted.Register(handlers...)
+	return &mgr.accepted
 }
 
 // Completed registers an event handler that is invoked when the trade is completed.
-func (mgr *Manager) Completed(handler g.EventHandler[Args]) {
-	mgr.completed.Register(handler)
+func (mgr *Manager) Completed(handlers ...g.EventHandler[Args]) *g.Event[Args] {
+	mgr.completed.Register(handlers...)
+	return &mgr.completed
 }
 
 // Closed registers an event that is invoked when the trade is closed.
-func (mgr *Manager) Closed(handler g.EventHandler[Args]) {
-	mgr.closed.Register(handler)
+func (mgr *Manager) Closed(handlers ...g.EventHandler[Args]) *g.Event[Args] {
+	mgr.closed.Register(handlers...)
+	return &mgr.closed
 }
@@ -0,0 +1,126 @@
+package trade
+
+import (
+	"strconv"
+
+	g "xabbo.b7c.io/goearth"
+	"xabbo.b7c.io/goearth/inventory"
+	"xabbo.b7c.io/goearth/room"
+)
+
+// Stage represents the stage of a trade.
+type Stage int
+
+const (
+	// StageClosed indicates that no trade is open.
+	StageClosed Stage = iota
+	// StageOffering indicates that items are being offered and accepted.
+	StageOffering
+	// StageConfirming indicates that both users have accepted and the trade is awaiting confirmation.
+	StageConfirming
+	// StageCompleted indicates that the trade has been completed.
+	StageCompleted
+)
+
+func (stage Stage) String() string {
+	switch stage {
+	case StageClosed:
+		return "closed"
+	case StageOffering:
+		return "offering"
+	case StageConfirming:
+		return "confirming"
+	case StageCompleted:
+		return "completed"
+	default:
+		return strconv.Itoa(int(stage))
+	}
+}
+
+// Offers is an array that holds the offers of the trader and tradee, respectively.
+type Offers [2]Offer
+
+// Trader returns the offer of the user who initiated the trade.
+func (offers Offers) Trader() Offer {
+	return offers[0]
+}
+
+// Tradee returns the offer of the user who received the trade request.
+func (offers Offers) Tradee() Offer {
+	return offers[1]
+}
+
+// Offer represents a user's offer in a trade.
+type Offer struct {
+	UserId    g.Id
+	Items     []Item
+	ItemCount int
+	Credits   int
+	// CanTrade, Accepted and Confirmed are not part of the item list and are tracked by the Manager.
+	CanTrade  bool
+	Accepted  bool
+	Confirmed bool
+}
+
+func (offer *Offer) Parse(p *g.Packet, pos *int) {
+	*offer = Offer{}
+	p.ReadPtr(pos, &offer.UserId, &offer.Items, &offer.ItemCount, &offer.Credits)
+}
+
+// Item represents an item in a trade offer.
+type Item struct {
+	ItemId        g.Id
+	Type          inventory.ItemType
+	Id            g.Id
+	Kind          int
+	Category      int
+	Groupable     bool
+	Data          room.StuffData
+	CreationDay   int
+	CreationMonth int
+	CreationYear  int
+	// Extra is only available for floor items.
+	Extra int
+}
+
+func (item Item) String() string {
+	return strconv.Itoa(item.Kind) + "(" + strconv.FormatInt(int64(item.ItemId), 10) + ")"
+}
+
+func (item *Item) Parse(p *g.Packet, pos *int) {
+	*item = Item{}
+	p.ReadPtr(pos, &item.ItemId, &item.Type, &item.Id, &item.Kind, &item.Category,
+		&item.Groupable, &item.Data, &item.CreationDay, &item.CreationMonth, &item.CreationYear)
+	if item.Type == inventory.Floor {
+		p.ReadPtr(pos, &item.Extra)
+	}
+}
+
+// Failure represents the reason a trade could not be performed.
+type Failure int
+
+const (
+	// FailureOpen indicates that the trade could not be opened.
+	FailureOpen Failure = iota + 1
+	// FailureNotOpen indicates that there is no trade open.
+	FailureNotOpen
+	// FailureYouNotAllowed indicates that the user is not allowed to trade.
+	FailureYouNotAllowed
+	// FailureOtherNotAllowed indicates that the other user is not allowed to trade.
+	FailureOtherNotAllowed
+)
+
+func (failure Failure) String() string {
+	switch failure {
+	case FailureOpen:
+		return "open failed"
+	case FailureNotOpen:
+		return "not open"
+	case FailureYouNotAllowed:
+		return "you are not allowed"
+	case FailureOtherNotAllowed:
+		return "other user not allowed"
+	default:
+		return strconv.Itoa(int(failure))
+	}
+}
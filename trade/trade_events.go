@@ -0,0 +1,78 @@
+package trade
+
+import g "xabbo.b7c.io/goearth"
+
+// Args holds the arguments for trade events.
+type Args struct {
+	Offers Offers
+}
+
+// AcceptArgs holds the arguments for trade accept events.
+type AcceptArgs struct {
+	UserId   g.Id
+	Accepted bool
+}
+
+// CloseArgs holds the arguments for trade close events.
+type CloseArgs struct {
+	UserId g.Id
+	Reason int
+	Offers Offers
+}
+
+// FailArgs holds the arguments for trade failure events.
+type FailArgs struct {
+	Failure Failure
+	// Reason and Name are only available when Failure is FailureOpen.
+	Reason int
+	Name   string
+}
+
+// Opened registers an event handler that is invoked when a trade is opened.
+func (mgr *Manager) Opened(handlers ...g.EventHandler[Args]) *g.Event[Args] {
+	mgr.opened.Register(handlers...)
+	return &mgr.opened
+}
+
+// Updated registers an event handler that is invoked when the trade offers are updated.
+func (mgr *Manager) Updated(handlers ...g.EventHandler[Args]) *g.Event[Args] {
+	mgr.updated.Register(handlers...)
+	return &mgr.updated
+}
+
+// Accepted registers an event handler that is invoked when a user accepts or unaccepts the trade.
+func (mgr *Manager) Accepted(handlers ...g.EventHandler[AcceptArgs]) *g.Event[AcceptArgs] {
+	mgr.accepted.Register(handlers...)
+	return &mgr.accepted
+}
+
+// Confirming registers an event handler that is invoked when both users have accepted
+// and the trade enters the confirmation stage.
+func (mgr *Manager) Confirming(handlers ...g.EventHandler[Args]) *g.Event[Args] {
+	mgr.confirming.Register(handlers...)
+	return &mgr.confirming
+}
+
+// Confirmed registers an event handler that is invoked when a user confirms the trade.
+func (mgr *Manager) Confirmed(handlers ...g.EventHandler[AcceptArgs]) *g.Event[AcceptArgs] {
+	mgr.confirmed.Register(handlers...)
+	return &mgr.confirmed
+}
+
+// Completed registers an event handler that is invoked when the trade is completed.
+func (mgr *Manager) Completed(handlers ...g.EventHandler[Args]) *g.Event[Args] {
+	mgr.completed.Register(handlers...)
+	return &mgr.completed
+}
+
+// Closed registers an event handler that is invoked when the trade is closed.
+func (mgr *Manager) Closed(handlers ...g.EventHandler[CloseArgs]) *g.Event[CloseArgs] {
+	mgr.closed.Register(handlers...)
+	return &mgr.closed
+}
+
+// Failed registers an event handler that is invoked when a trade could not be performed.
+func (mgr *Manager) Failed(handlers ...g.EventHandler[FailArgs]) *g.Event[FailArgs] {
+	mgr.failed.Register(handlers...)
+	return &mgr.failed
+}
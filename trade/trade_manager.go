@@ -0,0 +1,241 @@
+package trade
+
+import (
+	g "xabbo.b7c.io/goearth"
+	"xabbo.b7c.io/goearth/in"
+	"xabbo.b7c.io/goearth/internal/debug"
+	"xabbo.b7c.io/goearth/inventory"
+	"xabbo.b7c.io/goearth/out"
+)
+
+var dbg = debug.NewLogger("[trade]")
+
+// Manager tracks the state of trades.
+type Manager struct {
+	ix         g.Interceptor
+	opened     g.Event[Args]
+	updated    g.Event[Args]
+	accepted   g.Event[AcceptArgs]
+	confirming g.Event[Args]
+	confirmed  g.Event[AcceptArgs]
+	completed  g.Event[Args]
+	closed     g.Event[CloseArgs]
+	failed     g.Event[FailArgs]
+
+	Stage  Stage
+	Offers Offers
+}
+
+// NewManager creates a new trade Manager using the provided extension.
+func NewManager(ix g.Interceptor) *Manager {
+	mgr := &Manager{ix: ix}
+	ix.Intercept(in.TradingOpen).With(mgr.handleTradingOpen)
+	ix.Intercept(in.TradingItemList).With(mgr.handleTradingItemList)
+	ix.Intercept(in.TradingAccept).With(mgr.handleTradingAccept)
+	ix.Intercept(in.TradingConfirmation).With(mgr.handleTradingConfirmation)
+	ix.Intercept(in.TradingCompleted).With(mgr.handleTradingCompleted)
+	ix.Intercept(in.TradingClose).With(mgr.handleTradingClose)
+	ix.Intercept(in.TradeOpenFailed).With(mgr.handleTradeOpenFailed)
+	ix.Intercept(in.TradingNotOpen).With(mgr.handleFailure(FailureNotOpen))
+	ix.Intercept(in.TradingYouAreNotAllowed).With(mgr.handleFailure(FailureYouNotAllowed))
+	ix.Intercept(in.TradingOtherNotAllowed).With(mgr.handleFailure(FailureOtherNotAllowed))
+	return mgr
+}
+
+// Trading returns whether a trade is currently open.
+func (mgr *Manager) Trading() bool {
+	return mgr.Stage != StageClosed
+}
+
+// offer returns the offer of the user with the specified ID in the current trade.
+func (mgr *Manager) offer(userId g.Id) *Offer {
+	for i := range mgr.Offers {
+		if mgr.Offers[i].UserId == userId {
+			return &mgr.Offers[i]
+		}
+	}
+	return nil
+}
+
+// Open opens a trade with the entity at the specified index in the room.
+func (mgr *Manager) Open(index int) {
+	mgr.ix.Send(out.OpenTrading, index)
+}
+
+// Offer offers the items with the specified IDs in the current trade.
+func (mgr *Manager) Offer(itemIds ...g.Id) {
+	switch len(itemIds) {
+	case 0:
+	case 1:
+		mgr.ix.Send(out.AddItemToTrade, itemIds[0])
+	default:
+		values := make([]any, 0, 1+len(itemIds))
+		values = append(values, g.Length(len(itemIds)))
+		for _, itemId := range itemIds {
+			values = append(values, itemId)
+		}
+		mgr.ix.Send(out.AddItemsToTrade, values...)
+	}
+}
+
+// OfferItem offers the specified inventory items in the current trade.
+func (mgr *Manager) OfferItem(items ...inventory.Item) {
+	itemIds := make([]g.Id, len(items))
+	for i, item := range items {
+		itemIds[i] = item.ItemId
+	}
+	mgr.Offer(itemIds...)
+}
+
+// Remove removes the item with the specified ID from the current trade.
+func (mgr *Manager) Remove(itemId g.Id) {
+	mgr.ix.Send(out.RemoveItemFromTrade, itemId)
+}
+
+// Accept accepts the trade.
+func (mgr *Manager) Accept() {
+	mgr.ix.Send(out.AcceptTrading)
+}
+
+// Unaccept unaccepts the trade.
+func (mgr *Manager) Unaccept() {
+	mgr.ix.Send(out.UnacceptTrading)
+}
+
+// Confirm confirms the trade once it has entered the confirmation stage.
+func (mgr *Manager) Confirm() {
+	mgr.ix.Send(out.ConfirmAcceptTrading)
+}
+
+// Decline declines the trade while it is in the confirmation stage.
+func (mgr *Manager) Decline() {
+	mgr.ix.Send(out.ConfirmDeclineTrading)
+}
+
+// Cancel closes the current trade.
+func (mgr *Manager) Cancel() {
+	mgr.ix.Send(out.CloseTrading)
+}
+
+func (mgr *Manager) reset() {
+	mgr.Stage = StageClosed
+	mgr.Offers = Offers{}
+}
+
+// handlers
+
+func (mgr *Manager) handleTradingOpen(e *g.Intercept) {
+	var traderId, tradeeId g.Id
+	var traderCanTrade, tradeeCanTrade int
+	e.Packet.Read(&traderId, &traderCanTrade, &tradeeId, &tradeeCanTrade)
+
+	mgr.Stage = StageOffering
+	mgr.Offers = Offers{
+		{UserId: traderId, CanTrade: traderCanTrade != 0},
+		{UserId: tradeeId, CanTrade: tradeeCanTrade != 0},
+	}
+	mgr.opened.Dispatch(Args{mgr.Offers})
+	dbg.Printf("trade opened (trader: %d, tradee: %d)", traderId, tradeeId)
+}
+
+func (mgr *Manager) handleTradingItemList(e *g.Intercept) {
+	if !mgr.Trading() {
+		return
+	}
+
+	var offers Offers
+	e.Packet.Read(&offers)
+
+	for _, update := range offers {
+		offer := mgr.offer(update.UserId)
+		if offer == nil {
+			dbg.Printf("WARNING: failed to find offer for user %d", update.UserId)
+			continue
+		}
+		offer.Items = update.Items
+		offer.ItemCount = update.ItemCount
+		offer.Credits = update.Credits
+	}
+
+	mgr.updated.Dispatch(Args{mgr.Offers})
+
+	dbg.Printf("trade updated")
+	for _, offer := range mgr.Offers {
+		dbg.Printf("%d: %d item(s) (accepted: %t)", offer.UserId, len(offer.Items), offer.Accepted)
+	}
+}
+
+func (mgr *Manager) handleTradingAccept(e *g.Intercept) {
+	if !mgr.Trading() {
+		return
+	}
+
+	var userId g.Id
+	var accepted int
+	e.Packet.Read(&userId, &accepted)
+
+	offer := mgr.offer(userId)
+	if offer == nil {
+		dbg.Printf("WARNING: failed to find offer for user %d", userId)
+		return
+	}
+
+	args := AcceptArgs{userId, accepted != 0}
+	if mgr.Stage == StageConfirming {
+		offer.Confirmed = args.Accepted
+		mgr.confirmed.Dispatch(args)
+	} else {
+		offer.Accepted = args.Accepted
+		mgr.accepted.Dispatch(args)
+	}
+}
+
+func (mgr *Manager) handleTradingConfirmation(e *g.Intercept) {
+	if !mgr.Trading() {
+		return
+	}
+
+	mgr.Stage = StageConfirming
+	mgr.confirming.Dispatch(Args{mgr.Offers})
+	dbg.Printf("trade confirming")
+}
+
+func (mgr *Manager) handleTradingCompleted(e *g.Intercept) {
+	if !mgr.Trading() {
+		return
+	}
+
+	mgr.Stage = StageCompleted
+	mgr.completed.Dispatch(Args{mgr.Offers})
+	mgr.reset()
+	dbg.Printf("trade completed")
+}
+
+func (mgr *Manager) handleTradingClose(e *g.Intercept) {
+	if !mgr.Trading() {
+		return
+	}
+
+	var userId g.Id
+	var reason int
+	e.Packet.Read(&userId, &reason)
+
+	offers := mgr.Offers
+	mgr.reset()
+	mgr.closed.Dispatch(CloseArgs{userId, reason, offers})
+	dbg.Printf("trade closed (user: %d, reason: %d)", userId, reason)
+}
+
+func (mgr *Manager) handleTradeOpenFailed(e *g.Intercept) {
+	args := FailArgs{Failure: FailureOpen}
+	e.Packet.Read(&args.Reason, &args.Name)
+	mgr.failed.Dispatch(args)
+	dbg.Printf("trade open failed (reason: %d, name: %q)", args.Reason, args.Name)
+}
+
+func (mgr *Manager) handleFailure(failure Failure) g.InterceptHandler {
+	return func(e *g.Intercept) {
+		mgr.failed.Dispatch(FailArgs{Failure: failure})
+		dbg.Printf("trade failed (%s)", failure)
+	}
+}
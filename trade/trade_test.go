@@ -0,0 +1,42 @@
+package trade
+
+import (
+	"reflect"
+	"testing"
+
+	g "xabbo.b7c.io/goearth"
+	"xabbo.b7c.io/goearth/inventory"
+	"xabbo.b7c.io/goearth/room"
+)
+
+func TestOffers(t *testing.T) {
+	pkt := &g.Packet{Client: g.Flash, Header: g.Header{Dir: g.In}}
+	// trader
+	pkt.Write(1, 2)
+	pkt.Write(10, "S", 11, 100, 1, false, room.StuffData{Value: "1"}, 1, 2, 2020, 0)
+	pkt.Write(20, "I", 21, 200, 1, true, room.StuffData{Format: room.EmptyFormat}, 3, 4, 2021)
+	pkt.Write(2, 0)
+	// tradee
+	pkt.Write(2, 0, 0, 50)
+
+	var offers Offers
+	pkt.Pos = 0
+	pkt.Read(&offers)
+
+	if pkt.Pos != pkt.Length() {
+		t.Fatal("parser failed to read entire packet")
+	}
+
+	expected := Offers{
+		{UserId: 1, ItemCount: 2, Items: []Item{
+			{ItemId: 10, Type: inventory.Floor, Id: 11, Kind: 100, Category: 1,
+				Data: room.StuffData{Value: "1"}, CreationDay: 1, CreationMonth: 2, CreationYear: 2020},
+			{ItemId: 20, Type: inventory.Wall, Id: 21, Kind: 200, Category: 1, Groupable: true,
+				Data: room.StuffData{Format: room.EmptyFormat}, CreationDay: 3, CreationMonth: 4, CreationYear: 2021},
+		}},
+		{UserId: 2, Items: []Item{}, Credits: 50},
+	}
+	if !reflect.DeepEqual(expected, offers) {
+		t.Fatalf("incorrect offers, expected: %+v, actual: %+v", expected, offers)
+	}
+}